@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/camunda/zeebe-changelog/pkg/audit"
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/urfave/cli/v3"
+)
+
+func auditLabels(_ context.Context, cmd *cli.Command) error {
+	token := cmd.String(gitApiTokenFlag)
+	target := cmd.String(targetFlag)
+	label := cmd.String(labelFlag)
+	format := cmd.String(formatFlag)
+
+	if format != tableFormat && format != jsonFormat {
+		return usageError(fmt.Errorf("unknown format %q, expected one of: %s, %s", format, tableFormat, jsonFormat))
+	}
+
+	allowedRepositories, err := parseAllowedRepositories(cmd)
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient(token)
+
+	references, from, err := collectReferences(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	referenced := make([]audit.Issue, 0, len(references))
+	for _, reference := range references {
+		referenced = append(referenced, audit.Issue{Repository: reference.Repository(), Number: reference.ID, URL: reference.URL()})
+	}
+
+	var labeled []audit.Issue
+	for _, repository := range allowedRepositories {
+		owner, repo, _ := gitlog.ParseRepository(repository)
+
+		log.Println("Fetching issues with label", label, "in", repository)
+		issues, err := client.ListIssues(owner, repo, label)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			labeled = append(labeled, audit.Issue{
+				Repository:  repository,
+				Number:      issue.Number(),
+				URL:         issue.URL(),
+				Title:       issue.Title(),
+				State:       issue.State(),
+				StateReason: issue.StateReason(),
+			})
+		}
+	}
+
+	result := audit.Audit(label, from, target, labeled, referenced)
+
+	if format == jsonFormat {
+		content, err := result.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(content))
+		return nil
+	}
+
+	fmt.Print(result.Table())
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	exitCodeError            = 1
+	exitCodeInvalidUsage     = 2
+	exitCodeInvalidRange     = 3
+	exitCodeNotFound         = 4
+	exitCodePermissionDenied = 5
+	exitCodeRateLimited      = 6
+)
+
+// errInvalidUsage marks errors caused by invalid flags or configuration.
+var errInvalidUsage = errors.New("invalid usage")
+
+func usageError(err error) error {
+	return fmt.Errorf("%w: %w", errInvalidUsage, err)
+}
+
+// onUsageError marks flag parse errors and missing required flags as usage errors, after
+// showing the help of the command like cli does without this handler.
+func onUsageError(_ context.Context, cmd *cli.Command, err error, _ bool) error {
+	_ = cli.ShowSubcommandHelp(cmd)
+	return usageError(err)
+}
+
+// exitCode maps the failure class of the error to the exit code of the process, so that
+// scripts can react to e.g. rate limits differently than to missing permissions.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, errInvalidUsage):
+		return exitCodeInvalidUsage
+	case errors.Is(err, gitlog.ErrInvalidRange):
+		return exitCodeInvalidRange
+	case errors.Is(err, github.ErrRateLimited):
+		return exitCodeRateLimited
+	case errors.Is(err, github.ErrPermissionDenied):
+		return exitCodePermissionDenied
+	case errors.Is(err, github.ErrNotFound):
+		return exitCodeNotFound
+	default:
+		return exitCodeError
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode_UsageErrorsOfCli(t *testing.T) {
+	tests := map[string][]string{
+		"Missing required flag": {"zcl", "generate", "--token", "t"},
+		"Unknown flag":          {"zcl", "generate", "--unknown"},
+		"Invalid flag value":    {"zcl", "add-labels", "--workers", "many"},
+		"Unknown root flag":     {"zcl", "--unknown", "generate"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := createApp()
+			app.Writer = io.Discard
+			app.ErrWriter = io.Discard
+
+			err := app.Run(context.Background(), args)
+
+			assert.Error(t, err)
+			assert.Equal(t, exitCodeInvalidUsage, exitCode(err))
+		})
+	}
+}
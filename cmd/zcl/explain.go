@@ -0,0 +1,238 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/urfave/cli/v3"
+)
+
+func explainIssue(_ context.Context, cmd *cli.Command) error {
+	target := cmd.String(targetFlag)
+	resolvePRs := cmd.Bool(resolvePRsFlag)
+	scanPRBodies := cmd.Bool(scanPRBodiesFlag)
+
+	if cmd.Args().Len() != 1 {
+		return usageError(errors.New("expected exactly one issue, e.g. 21345 or camunda/camunda#21345"))
+	}
+	issue, err := gitlog.ParseIssueReference(cmd.Args().First(), cmd.String(githubOrgFlag), cmd.String(githubRepoFlag))
+	if err != nil {
+		return usageError(err)
+	}
+	if (resolvePRs || scanPRBodies) && cmd.String(gitApiTokenFlag) == "" {
+		return usageError(fmt.Errorf("--%s is required to trace references with --%s or --%s", gitApiTokenFlag, resolvePRsFlag, scanPRBodiesFlag))
+	}
+	allowedRepositories, err := parseAllowedRepositories(cmd)
+	if err != nil {
+		return err
+	}
+
+	commits, extractor, from, err := collectCommits(cmd)
+	if err != nil {
+		return err
+	}
+
+	traces := extractor.Trace(commits, issue)
+	branches := map[int]string{}
+	if resolvePRs || scanPRBodies {
+		client := github.NewClient(cmd.String(gitApiTokenFlag))
+		var traced []gitlog.Trace
+		traced, branches, err = tracePullRequests(cmd, client, extractor, commits, issue, traces)
+		if err != nil {
+			return err
+		}
+		traces = append(traces, traced...)
+		sortTraces(traces, commits)
+	}
+
+	if len(traces) == 0 {
+		hint := fmt.Sprintf("--%s=%s to scan all commits", historyFlag, gitlog.AllMode)
+		if !resolvePRs || !scanPRBodies {
+			hint += fmt.Sprintf(", or --%s and --%s to include pull requests", resolvePRsFlag, scanPRBodiesFlag)
+		}
+		fmt.Printf("%s is not referenced by any of the %d commits in %s..%s, consider %s\n", issue, len(commits), from, target, hint)
+		return nil
+	}
+
+	fmt.Printf("Found %d reference(s) to %s in %s..%s\n", len(traces), issue, from, target)
+	for _, trace := range traces {
+		printTrace(trace, branches)
+	}
+
+	if _, rejected := filterAllowedRepositories([]gitlog.IssueReference{issue}, allowedRepositories); len(rejected) > 0 {
+		fmt.Printf("\nRepository %s is not allowed (%s), so add-labels skips %s\n", issue.Repository(), strings.Join(allowedRepositories, ", "), issue)
+	}
+	return nil
+}
+
+// tracePullRequests returns the references of the issue which are only known to GitHub:
+// pull requests which close the issue with --resolve-prs and references in the title and
+// body of the merged pull requests with --scan-pr-bodies. The head branches of the pull
+// requests of all traces are returned by pull request number, since squash merges do not
+// name the merged branch.
+func tracePullRequests(cmd *cli.Command, client *github.Client, extractor *gitlog.Extractor, commits []gitlog.Commit, issue gitlog.IssueReference, commitTraces []gitlog.Trace) ([]gitlog.Trace, map[int]string, error) {
+	githubOrg := cmd.String(githubOrgFlag)
+	githubRepo := cmd.String(githubRepoFlag)
+
+	commitsBySHA := make(map[string]*gitlog.Commit, len(commits))
+	commitsByPullRequest := map[int]*gitlog.Commit{}
+	for i := range commits {
+		commitsBySHA[commits[i].SHA] = &commits[i]
+		if number, ok := commits[i].PullRequest(); ok && commitsByPullRequest[number] == nil {
+			commitsByPullRequest[number] = &commits[i]
+		}
+	}
+
+	var traces []gitlog.Trace
+	if cmd.Bool(resolvePRsFlag) {
+		shas := make([]string, 0, len(commits))
+		for _, commit := range commits {
+			shas = append(shas, commit.SHA)
+		}
+
+		log.Println("Resolving pull requests and closing issues of", len(commits), "commits")
+		closingIssues, err := client.ResolveClosingIssues(githubOrg, githubRepo, shas)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, closingIssue := range closingIssues {
+			reference := gitlog.IssueReference{Owner: closingIssue.Owner, Repo: closingIssue.Repo, ID: closingIssue.Number}
+			if !reference.SameIssue(issue) {
+				continue
+			}
+
+			line := fmt.Sprintf("closed by pull request #%d", closingIssue.PullRequest)
+			if reference.SameIssue(gitlog.IssueReference{Owner: githubOrg, Repo: githubRepo, ID: closingIssue.PullRequest}) {
+				line = fmt.Sprintf("pull request #%d is associated with the commit", closingIssue.PullRequest)
+			}
+			traces = append(traces, gitlog.Trace{
+				Commit:      commitsBySHA[closingIssue.SHA],
+				Line:        line,
+				Source:      gitlog.ClosingIssueSource,
+				PullRequest: closingIssue.PullRequest,
+			})
+		}
+	}
+
+	// the descriptions of all merged pull requests are scanned, otherwise only the head
+	// branches of the traced pull requests are needed
+	var numbers []int
+	if cmd.Bool(scanPRBodiesFlag) {
+		for number := range commitsByPullRequest {
+			numbers = append(numbers, number)
+		}
+	}
+	for _, trace := range slices.Concat(commitTraces, traces) {
+		if number := tracedPullRequest(trace); number != 0 && !slices.Contains(numbers, number) {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.Sort(numbers)
+
+	pullRequests, err := client.FetchPullRequests(githubOrg, githubRepo, numbers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	branches := make(map[int]string, len(pullRequests))
+	for _, pullRequest := range pullRequests {
+		branches[pullRequest.Number] = pullRequest.HeadBranch()
+
+		if !cmd.Bool(scanPRBodiesFlag) || commitsByPullRequest[pullRequest.Number] == nil {
+			continue
+		}
+		for _, line := range extractor.TraceText(pullRequest.Title+"\n"+pullRequest.Body, issue) {
+			traces = append(traces, gitlog.Trace{
+				Commit:      commitsByPullRequest[pullRequest.Number],
+				Line:        line,
+				Source:      gitlog.PullRequestSource,
+				PullRequest: pullRequest.Number,
+			})
+		}
+	}
+
+	return traces, branches, nil
+}
+
+// tracedPullRequest returns the number of the pull request of the trace, or of the pull
+// request merged by the commit of the trace.
+func tracedPullRequest(trace gitlog.Trace) int {
+	if trace.PullRequest != 0 {
+		return trace.PullRequest
+	}
+	if number, ok := trace.Commit.PullRequest(); ok {
+		return number
+	}
+	return 0
+}
+
+// sortTraces orders the traces by the position of their commits in the history, keeping
+// the order of the traces of a commit.
+func sortTraces(traces []gitlog.Trace, commits []gitlog.Commit) {
+	positions := make(map[string]int, len(commits))
+	for i, commit := range commits {
+		positions[commit.SHA] = i
+	}
+	slices.SortStableFunc(traces, func(a, b gitlog.Trace) int {
+		return cmp.Compare(positions[a.Commit.SHA], positions[b.Commit.SHA])
+	})
+}
+
+func printTrace(trace gitlog.Trace, branches map[int]string) {
+	commit := trace.Commit
+	pullRequest := tracedPullRequest(trace)
+
+	fmt.Printf("\ncommit %s %s\n", commit.SHA, commit.Subject)
+	fmt.Printf("  Author:   %s <%s>\n", commit.Author.Name, commit.Author.Email)
+	fmt.Printf("  Date:     %s\n", commit.CommitterDate.Format(time.RFC3339))
+	fmt.Printf("  Parents:  %s\n", describeParents(commit))
+	if branch, ok := commit.MergedBranch(); ok {
+		fmt.Printf("  Branch:   %s\n", branch)
+	} else if branch := branches[pullRequest]; branch != "" {
+		fmt.Printf("  Branch:   %s\n", branch)
+	}
+	if pullRequest != 0 {
+		fmt.Printf("  PR:       #%d\n", pullRequest)
+	}
+	fmt.Printf("  Backport: %s\n", yesNo(commit.IsBackport()))
+	fmt.Printf("  Source:   %s\n", trace.Source)
+	fmt.Printf("  Matched:  %s\n", trace.Line)
+}
+
+// describeParents lists the parents of the commit, for merges the first parent is the
+// branch merged into and the others are the merged branches.
+func describeParents(commit *gitlog.Commit) string {
+	if len(commit.Parents) == 0 {
+		return "none (root commit)"
+	}
+
+	parents := make([]string, 0, len(commit.Parents))
+	for i, parent := range commit.Parents {
+		short := (&gitlog.Commit{SHA: parent}).ShortSHA()
+		switch {
+		case !commit.IsMerge():
+			parents = append(parents, short)
+		case i == 0:
+			parents = append(parents, short+" (merged into)")
+		default:
+			parents = append(parents, short+" (merged)")
+		}
+	}
+	return strings.Join(parents, ", ")
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
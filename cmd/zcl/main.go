@@ -1,15 +1,26 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"text/template"
 
+	"github.com/camunda/zeebe-changelog/pkg/changelogfile"
+	"github.com/camunda/zeebe-changelog/pkg/config"
 	"github.com/camunda/zeebe-changelog/pkg/github"
 	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/journal"
+	"github.com/camunda/zeebe-changelog/pkg/labelsync"
 	"github.com/camunda/zeebe-changelog/pkg/progress"
+	"github.com/camunda/zeebe-changelog/pkg/report"
 	"github.com/urfave/cli/v3"
 )
 
@@ -23,19 +34,93 @@ const (
 	labelEnv          = "ZCL_LABEL"
 	fromFlag          = "from"
 	fromEnv           = "ZCL_FROM_REV"
+	fromAuto          = "auto"
 	targetFlag        = "target"
 	targetEnv         = "ZCL_TARGET_REV"
 	githubOrgFlag     = "org"
 	githubOrgEnv      = "ZCL_ORG"
-	githubOrgDefault  = "zeebe-io"
+	githubOrgDefault  = "camunda"
 	githubRepoFlag    = "repo"
 	githubRepoEnv     = "ZCL_REPO"
-	githubRepoDefault = "zeebe"
+	githubRepoDefault = "camunda"
 	workersFlag       = "workers"
 	workersEnv        = "ZCL_WORKERS"
 	workersDefault    = 10
 	dryRunFlag        = "dry-run"
 	dryRunEnv         = "ZCL_DRY_RUN"
+
+	referenceKeywordFlag = "reference-keyword"
+	referenceKeywordEnv  = "ZCL_REFERENCE_KEYWORDS"
+	referenceRepoFlag    = "reference-repo"
+	referenceRepoEnv     = "ZCL_REFERENCE_REPOS"
+	referencePatternFlag = "reference-pattern"
+	referencePatternEnv  = "ZCL_REFERENCE_PATTERNS"
+	referenceAliasFlag   = "reference-alias"
+	referenceAliasEnv    = "ZCL_REFERENCE_ALIASES"
+	referenceConfigFlag  = "reference-config"
+	referenceConfigEnv   = "ZCL_REFERENCE_CONFIG"
+	allowedRepoFlag      = "allowed-repo"
+	allowedRepoEnv       = "ZCL_ALLOWED_REPOS"
+	historyFlag          = "history"
+	historyEnv           = "ZCL_HISTORY"
+	includePathFlag      = "include-path"
+	includePathEnv       = "ZCL_INCLUDE_PATHS"
+	excludePathFlag      = "exclude-path"
+	excludePathEnv       = "ZCL_EXCLUDE_PATHS"
+	resolvePRsFlag       = "resolve-prs"
+	resolvePRsEnv        = "ZCL_RESOLVE_PRS"
+	scanPRBodiesFlag     = "scan-pr-bodies"
+	scanPRBodiesEnv      = "ZCL_SCAN_PR_BODIES"
+	journalFlag          = "journal"
+	journalEnv           = "ZCL_JOURNAL"
+	journalFileName      = "journal.jsonl"
+	resumeFlag           = "resume"
+	resumeEnv            = "ZCL_RESUME"
+	reportFlag           = "report"
+	reportEnv            = "ZCL_REPORT"
+	reportFileFlag       = "report-file"
+	reportFileEnv        = "ZCL_REPORT_FILE"
+	reportFileDefault    = "zcl-report"
+	issuesFlag           = "issues"
+	issuesEnv            = "ZCL_ISSUES"
+	allFlag              = "all"
+	allEnv               = "ZCL_ALL"
+	yesFlag              = "yes"
+	yesEnv               = "ZCL_YES"
+	syncFlag             = "sync"
+	syncEnv              = "ZCL_SYNC"
+	formatFlag           = "format"
+	formatEnv            = "ZCL_FORMAT"
+	categoriesFlag       = "categories"
+	categoriesEnv        = "ZCL_CATEGORIES"
+	templateFlag         = "template"
+	templateEnv          = "ZCL_TEMPLATE"
+	outputFlag           = "output"
+	outputEnv            = "ZCL_OUTPUT"
+	insertFlag           = "insert"
+	insertEnv            = "ZCL_INSERT"
+	markerFlag           = "marker"
+	markerEnv            = "ZCL_MARKER"
+	tagFlag              = "tag"
+	tagEnv               = "ZCL_TAG"
+	stateFlag            = "state"
+	stateEnv             = "ZCL_STATE"
+	stateFileName        = "release.json"
+	skipFlag             = "skip"
+	skipEnv              = "ZCL_SKIP"
+	verifyTimeoutFlag    = "verify-timeout"
+	verifyTimeoutEnv     = "ZCL_VERIFY_TIMEOUT"
+	configFlag           = "config"
+	configEnv            = "ZCL_CONFIG"
+	profileFlag          = "profile"
+	profileEnv           = "ZCL_PROFILE"
+)
+
+// output formats of the format flag
+const (
+	tableFormat    = "table"
+	jsonFormat     = "json"
+	markdownFormat = "markdown"
 )
 
 var (
@@ -47,187 +132,1068 @@ func main() {
 	app := createApp()
 	err := app.Run(context.Background(), os.Args)
 	if err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		os.Exit(exitCode(err))
 	}
 }
 
 func createApp() *cli.Command {
-	return &cli.Command{
+	app := &cli.Command{
 		Name:    appName,
 		Usage:   "Zeebe Changelog Helper",
 		Version: fmt.Sprintf("%s (commit: %s)", version, commit),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    configFlag,
+				Usage:   "Configuration file with profiles (default: " + config.FileName + " in the root of the git working tree)",
+				Sources: cli.EnvVars(configEnv),
+			},
+			&cli.StringFlag{
+				Name:    profileFlag,
+				Usage:   "Profile of the configuration file to take flag values from (default: the default profile of the file)",
+				Sources: cli.EnvVars(profileEnv),
+			},
+		},
 		Commands: []*cli.Command{
 			{
 				Name:    "add-labels",
 				Aliases: []string{"a"},
 				Usage:   "Add GitHub labels to issues and PRs",
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:    gitDirFlag,
-						Usage:   "Git working directory",
-						Sources: cli.EnvVars(gitDirEnv),
-						Value:   ".",
-					},
-					&cli.StringFlag{
-						Name:     labelFlag,
-						Sources:  cli.EnvVars(labelEnv),
-						Usage:    "GitHub label to attach to issues and PRs",
-						Required: true,
-					},
-					&cli.StringFlag{
-						Name:     fromFlag,
-						Sources:  cli.EnvVars(fromEnv),
-						Usage:    "Git revision to start start processing",
-						Required: true,
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:     labelFlag,
+							Sources:  cli.EnvVars(labelEnv),
+							Usage:    "GitHub label to attach to issues and PRs",
+							Required: true,
+						},
 					},
-					&cli.StringFlag{
-						Name:     targetFlag,
-						Sources:  cli.EnvVars(targetEnv),
-						Usage:    "Git revision to stop commit processing",
-						Required: true,
-					},
-					&cli.StringFlag{
-						Name:     gitApiTokenFlag,
-						Usage:    "GitHub API Token",
-						Sources:  cli.EnvVars(gitApiTokenEnv),
-						Required: true,
-					},
-					&cli.StringFlag{
-						Name:    githubOrgFlag,
-						Usage:   "GitHub organization",
-						Sources: cli.EnvVars(githubOrgEnv),
-						Value:   githubOrgDefault,
-					},
-					&cli.StringFlag{
-						Name:    githubRepoFlag,
-						Usage:   "GitHub repository",
-						Sources: cli.EnvVars(githubRepoEnv),
-						Value:   githubRepoDefault,
+					githubFlags(),
+					rangeFlags(true),
+					referenceFlags(),
+					workerFlags("Print issues that would be labeled without making any changes"),
+					labelingFlags(),
+					reportFlags(),
+				),
+				Action: addLabels,
+			},
+			{
+				Name:    "remove-labels",
+				Aliases: []string{"r"},
+				Usage:   "Remove a GitHub label from issues and PRs, e.g. to undo a labeling run with a wrong range",
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:     labelFlag,
+							Sources:  cli.EnvVars(labelEnv),
+							Usage:    "GitHub label to remove from issues and PRs",
+							Required: true,
+						},
+						&cli.StringSliceFlag{
+							Name:    issuesFlag,
+							Usage:   "Issue to remove the label from, as number or owner/repo#number, instead of the issues referenced in --from..--target",
+							Sources: cli.EnvVars(issuesEnv),
+						},
+						&cli.BoolFlag{
+							Name:    allFlag,
+							Usage:   "Remove the label from all issues of --org/--repo which currently carry it",
+							Sources: cli.EnvVars(allEnv),
+						},
+						&cli.BoolFlag{
+							Name:    yesFlag,
+							Usage:   "Do not ask for confirmation before removing the label from all issues",
+							Sources: cli.EnvVars(yesEnv),
+						},
 					},
-					&cli.IntFlag{
-						Name:    workersFlag,
-						Usage:   "Number of concurrent workers for labeling",
-						Sources: cli.EnvVars(workersEnv),
-						Value:   workersDefault,
+					githubFlags(),
+					rangeFlags(false),
+					referenceFlags(),
+					workerFlags("Print issues the label would be removed from without making any changes"),
+					reportFlags(),
+				),
+				Action: removeLabels,
+			},
+			{
+				Name:  "audit",
+				Usage: "Compare the issues carrying a label with the issues referenced in a git range",
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:     labelFlag,
+							Sources:  cli.EnvVars(labelEnv),
+							Usage:    "GitHub label to audit",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:    formatFlag,
+							Usage:   "Output format: table or json",
+							Sources: cli.EnvVars(formatEnv),
+							Value:   tableFormat,
+						},
 					},
-					&cli.BoolFlag{
-						Name:    dryRunFlag,
-						Usage:   "Print issues that would be labeled without making any changes",
-						Sources: cli.EnvVars(dryRunEnv),
+					githubFlags(),
+					rangeFlags(true),
+					referenceFlags(),
+				),
+				Action: auditLabels,
+			},
+			{
+				Name:      "explain",
+				Usage:     "Show why an issue is part of a git range",
+				ArgsUsage: "<issue>",
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:    gitApiTokenFlag,
+							Usage:   "GitHub API Token, required for --" + resolvePRsFlag + " and --" + scanPRBodiesFlag,
+							Sources: cli.EnvVars(gitApiTokenEnv),
+						},
+						&cli.StringFlag{
+							Name:    githubOrgFlag,
+							Usage:   "GitHub organization",
+							Sources: cli.EnvVars(githubOrgEnv),
+							Value:   githubOrgDefault,
+						},
+						&cli.StringFlag{
+							Name:    githubRepoFlag,
+							Usage:   "GitHub repository",
+							Sources: cli.EnvVars(githubRepoEnv),
+							Value:   githubRepoDefault,
+						},
 					},
-				},
-				Action: addLabels,
+					rangeFlags(true),
+					referenceFlags(),
+				),
+				Action: explainIssue,
 			},
 			{
 				Name:    "generate",
 				Aliases: []string{"g"},
 				Usage:   "Generate change log",
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:     labelFlag,
-						Sources:  cli.EnvVars(labelEnv),
-						Usage:    "GitHub label name to generate changelog from",
-						Required: true,
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:     labelFlag,
+							Sources:  cli.EnvVars(labelEnv),
+							Usage:    "GitHub label name to generate changelog from",
+							Required: true,
+						},
 					},
-					&cli.StringFlag{
-						Name:     gitApiTokenFlag,
-						Usage:    "GitHub API Token",
-						Sources:  cli.EnvVars(gitApiTokenEnv),
-						Required: true,
+					githubFlags(),
+					changelogFlags(),
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:    formatFlag,
+							Usage:   "Output format: markdown, rendered with the template, or json",
+							Sources: cli.EnvVars(formatEnv),
+							Value:   markdownFormat,
+						},
 					},
-					&cli.StringFlag{
-						Name:    githubOrgFlag,
-						Usage:   "GitHub organization",
-						Sources: cli.EnvVars(githubOrgEnv),
-						Value:   githubOrgDefault,
+					outputFlags(),
+				),
+				Action: generateChangelog,
+			},
+			{
+				Name:  "publish",
+				Usage: "Write the changelog of a label to the draft release of a tag, which is created if it does not exist",
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:     labelFlag,
+							Sources:  cli.EnvVars(labelEnv),
+							Usage:    "GitHub label name to generate changelog from",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:     tagFlag,
+							Sources:  cli.EnvVars(tagEnv),
+							Usage:    "Tag of the release to publish the changelog to",
+							Required: true,
+						},
+						&cli.BoolFlag{
+							Name:    dryRunFlag,
+							Usage:   "Print the difference to the current release notes without changing the release",
+							Sources: cli.EnvVars(dryRunEnv),
+						},
 					},
-					&cli.StringFlag{
-						Name:    githubRepoFlag,
-						Usage:   "GitHub repository",
-						Sources: cli.EnvVars(githubRepoEnv),
-						Value:   githubRepoDefault,
+					githubFlags(),
+					changelogFlags(),
+				),
+				Action: publishReleaseNotes,
+			},
+			{
+				Name:  "release",
+				Usage: "Label the issues of a git range, verify the labels, generate the changelog and publish it in one resumable run",
+				Flags: slices.Concat(
+					[]cli.Flag{
+						&cli.StringFlag{
+							Name:    labelFlag,
+							Sources: cli.EnvVars(labelEnv),
+							Usage:   "GitHub label to attach to issues and PRs and to generate the changelog from (default: version:<target>)",
+						},
+						&cli.StringFlag{
+							Name:    tagFlag,
+							Sources: cli.EnvVars(tagEnv),
+							Usage:   "Tag of the release to publish the changelog to (default: the target)",
+						},
+						&cli.StringSliceFlag{
+							Name:    skipFlag,
+							Sources: cli.EnvVars(skipEnv),
+							Usage:   "Step to skip: " + strings.Join(releaseSteps, ", "),
+						},
+						&cli.StringFlag{
+							Name:    stateFlag,
+							Sources: cli.EnvVars(stateEnv),
+							Usage:   "File to record the completed steps in, a failed run is resumed at the failed step",
+							Value:   defaultCachePath(stateFileName),
+						},
+						&cli.DurationFlag{
+							Name:    verifyTimeoutFlag,
+							Sources: cli.EnvVars(verifyTimeoutEnv),
+							Usage:   "How long to wait until GitHub lists all labeled issues with the label",
+							Value:   verifyTimeoutDefault,
+						},
 					},
-				},
-				Action: generateChangelog,
+					githubFlags(),
+					rangeFlags(true),
+					referenceFlags(),
+					workerFlags("Print what every step would do without making any changes"),
+					labelingFlags(),
+					reportFlags(),
+					changelogFlags(),
+					outputFlags(),
+				),
+				Action: releaseChangelog,
 			},
 		},
 	}
+
+	app.OnUsageError = onUsageError
+	for _, command := range app.Commands {
+		command.Before = applyProfile
+		command.OnUsageError = onUsageError
+	}
+	return app
+}
+
+// defaultCachePath returns the file of the given name in the cache directory of the user,
+// so that runs in a git checkout, e.g. in CI, do not leave a file behind which could be
+// committed.
+func defaultCachePath(name string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, appName, name)
 }
 
-func addLabelsParallel(client *github.Client, githubOrg, githubRepo string, issueIds []int, label string, bar *progress.Bar, numWorkers int) {
+// labelingFlags returns the flags which control how add-labels resumes and reconciles runs.
+func labelingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    journalFlag,
+			Usage:   "File to record the outcome of labeling every issue in",
+			Sources: cli.EnvVars(journalEnv),
+			Value:   defaultCachePath(journalFileName),
+		},
+		&cli.BoolFlag{
+			Name:    resumeFlag,
+			Usage:   "Skip issues which were labeled successfully by a previous run with the same label and range, according to the journal",
+			Sources: cli.EnvVars(resumeEnv),
+		},
+		&cli.BoolFlag{
+			Name:    syncFlag,
+			Usage:   "Also remove the label from issues in the allowed repositories which are not referenced in the range",
+			Sources: cli.EnvVars(syncEnv),
+		},
+	}
+}
+
+// outputFlags returns the flags which write the changelog to a file.
+func outputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    outputFlag,
+			Usage:   "Write the changelog to this file instead of stdout",
+			Sources: cli.EnvVars(outputEnv),
+		},
+		&cli.BoolFlag{
+			Name:    insertFlag,
+			Usage:   "Insert the changelog below the marker of the --output file, or replace the changelog of the label generated before, instead of overwriting the file",
+			Sources: cli.EnvVars(insertEnv),
+		},
+		&cli.StringFlag{
+			Name:    markerFlag,
+			Usage:   "Line of the --output file below which --insert adds new changelogs",
+			Sources: cli.EnvVars(markerEnv),
+			Value:   changelogfile.DefaultMarker,
+		},
+	}
+}
+
+// changelogFlags returns the flags which configure the layout of the changelog.
+func changelogFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    categoriesFlag,
+			Usage:   "YAML file with the chapters and sections of the changelog (default: kind/ and scope/ labels)",
+			Sources: cli.EnvVars(categoriesEnv),
+		},
+		&cli.StringFlag{
+			Name:    templateFlag,
+			Usage:   "Go text/template file to render the changelog with (default: markdown with chapters and sections as headings)",
+			Sources: cli.EnvVars(templateEnv),
+		},
+	}
+}
+
+// githubFlags returns the flags which select the GitHub repository and credentials.
+func githubFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     gitApiTokenFlag,
+			Usage:    "GitHub API Token",
+			Sources:  cli.EnvVars(gitApiTokenEnv),
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    githubOrgFlag,
+			Usage:   "GitHub organization",
+			Sources: cli.EnvVars(githubOrgEnv),
+			Value:   githubOrgDefault,
+		},
+		&cli.StringFlag{
+			Name:    githubRepoFlag,
+			Usage:   "GitHub repository",
+			Sources: cli.EnvVars(githubRepoEnv),
+			Value:   githubRepoDefault,
+		},
+	}
+}
+
+// rangeFlags returns the flags which select the git range and how issue references are
+// extracted from its commits.
+func rangeFlags(required bool) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    gitDirFlag,
+			Usage:   "Git working directory",
+			Sources: cli.EnvVars(gitDirEnv),
+			Value:   ".",
+		},
+		&cli.StringFlag{
+			Name:     fromFlag,
+			Sources:  cli.EnvVars(fromEnv),
+			Usage:    "Git revision to start start processing, or 'auto' to detect the previous release tag of --target",
+			Required: required,
+		},
+		&cli.StringFlag{
+			Name:     targetFlag,
+			Sources:  cli.EnvVars(targetEnv),
+			Usage:    "Git revision to stop commit processing",
+			Required: required,
+		},
+		&cli.StringSliceFlag{
+			Name:    referenceKeywordFlag,
+			Usage:   "Keyword which marks a commit message line as issue reference (default: GitHub closing keywords, related, merge and backport)",
+			Sources: cli.EnvVars(referenceKeywordEnv),
+		},
+		&cli.StringSliceFlag{
+			Name:    referenceRepoFlag,
+			Usage:   "Repository in owner/repo notation whose references are also recognized as owner/repo/123 (default: --org/--repo, camunda/camunda and camunda/zeebe)",
+			Sources: cli.EnvVars(referenceRepoEnv),
+		},
+		&cli.StringSliceFlag{
+			Name:    referencePatternFlag,
+			Usage:   "Additional regular expression with a named group 'id' which matches issue references",
+			Sources: cli.EnvVars(referencePatternEnv),
+		},
+		&cli.StringSliceFlag{
+			Name:    referenceAliasFlag,
+			Usage:   "Previous name of a renamed repository as old-owner/old-repo=owner/repo, whose references belong to the renamed repository (default: camunda/zeebe=--org/--repo)",
+			Sources: cli.EnvVars(referenceAliasEnv),
+		},
+		&cli.StringFlag{
+			Name:    referenceConfigFlag,
+			Usage:   "YAML file with reference keywords, repositories, patterns and aliases",
+			Sources: cli.EnvVars(referenceConfigEnv),
+		},
+		&cli.StringFlag{
+			Name:    historyFlag,
+			Usage:   "Commits to scan for issue references: merges, first-parent (squash and rebase merges) or all",
+			Sources: cli.EnvVars(historyEnv),
+			Value:   string(gitlog.MergesMode),
+		},
+		&cli.StringSliceFlag{
+			Name:    includePathFlag,
+			Usage:   "Only consider commits which change paths matching the glob, e.g. 'zeebe/**'",
+			Sources: cli.EnvVars(includePathEnv),
+		},
+		&cli.StringSliceFlag{
+			Name:    excludePathFlag,
+			Usage:   "Ignore changes of paths matching the glob",
+			Sources: cli.EnvVars(excludePathEnv),
+		},
+	}
+}
+
+// referenceFlags returns the flags which select additional sources of issue references
+// and the repositories whose issues may be changed.
+func referenceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    resolvePRsFlag,
+			Usage:   "Resolve the pull requests of all commits and the issues they close via the GitHub API",
+			Sources: cli.EnvVars(resolvePRsEnv),
+		},
+		&cli.BoolFlag{
+			Name:    scanPRBodiesFlag,
+			Usage:   "Scan the title and body of the merged pull requests for issue references",
+			Sources: cli.EnvVars(scanPRBodiesEnv),
+		},
+		&cli.StringSliceFlag{
+			Name:    allowedRepoFlag,
+			Usage:   "Repository in owner/repo notation in which issues may be labeled (default: --org/--repo)",
+			Sources: cli.EnvVars(allowedRepoEnv),
+		},
+	}
+}
+
+func workerFlags(dryRunUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    workersFlag,
+			Usage:   "Number of concurrent workers for labeling",
+			Sources: cli.EnvVars(workersEnv),
+			Value:   workersDefault,
+		},
+		&cli.BoolFlag{
+			Name:    dryRunFlag,
+			Usage:   dryRunUsage,
+			Sources: cli.EnvVars(dryRunEnv),
+		},
+	}
+}
+
+func reportFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    reportFlag,
+			Usage:   "Write a report of the outcome of every issue in the given format: json or markdown",
+			Sources: cli.EnvVars(reportEnv),
+		},
+		&cli.StringFlag{
+			Name:    reportFileFlag,
+			Usage:   "File to write the report to (default: zcl-report.json or zcl-report.md)",
+			Sources: cli.EnvVars(reportFileEnv),
+		},
+	}
+}
+
+// processParallel runs process for every reference with a pool of workers. The outcome of
+// every reference is added to the report and recorded in the journal, if any.
+func processParallel(references []gitlog.IssueReference, bar *progress.Bar, numWorkers int, runJournal *journal.Journal, runReport *report.Report, process func(reference gitlog.IssueReference) (report.Outcome, error)) error {
 	// Use a worker pool pattern with reasonable concurrency
-	jobs := make(chan int, len(issueIds))
+	jobs := make(chan gitlog.IssueReference, len(references))
 	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errs []error
 
 	// Start worker goroutines
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for issueId := range jobs {
-				client.AddLabel(githubOrg, githubRepo, issueId, label)
+			for reference := range jobs {
+				outcome, err := process(reference)
+
+				result := newResult(reference, outcome, err)
+				runReport.Add(result)
+				if outcome != report.Failed {
+					err = nil
+				}
+
+				if runJournal != nil {
+					if journalErr := runJournal.Record(result); journalErr != nil {
+						err = errors.Join(err, journalErr)
+					}
+				}
+				if err != nil {
+					mutex.Lock()
+					errs = append(errs, err)
+					mutex.Unlock()
+				}
 				bar.Increase()
 			}
 		}()
 	}
 
-	// Send all issue IDs to workers
-	for _, id := range issueIds {
-		jobs <- id
+	// Send all issue references to workers
+	for _, reference := range references {
+		jobs <- reference
 	}
 	close(jobs)
 
 	// Wait for all workers to complete
 	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// labelOutcome returns the outcome of adding or removing a label based on the error of
+// the GitHub client.
+func labelOutcome(success report.Outcome, err error) report.Outcome {
+	switch {
+	case err == nil:
+		return success
+	case errors.Is(err, github.ErrNotLabelable):
+		return report.Skipped
+	default:
+		return report.Failed
+	}
+}
+
+func newResult(reference gitlog.IssueReference, outcome report.Outcome, err error) report.Result {
+	result := report.Result{
+		Repository: reference.Repository(),
+		Issue:      reference.ID,
+		URL:        reference.URL(),
+		Outcome:    outcome,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// parseReportFlags returns the format and file of the requested report, the format is
+// empty if no report was requested.
+func parseReportFlags(cmd *cli.Command) (report.Format, string, error) {
+	value := cmd.String(reportFlag)
+	if value == "" {
+		return "", "", nil
+	}
+
+	format, err := report.ParseFormat(value)
+	if err != nil {
+		return "", "", usageError(err)
+	}
+
+	file := cmd.String(reportFileFlag)
+	if file == "" {
+		file = reportFileDefault + format.Extension()
+	}
+	return format, file, nil
+}
+
+// finishReport prints the summary of the report and writes the report file, if requested.
+func finishReport(runReport *report.Report, format report.Format, file string) error {
+	fmt.Println()
+	fmt.Print(runReport.Summary())
+
+	if format == "" {
+		return nil
+	}
+	if err := runReport.Write(file, format); err != nil {
+		return err
+	}
+	log.Println("Wrote", format, "report to", file)
+	return nil
 }
 
 func addLabels(_ context.Context, cmd *cli.Command) error {
 	token := cmd.String(gitApiTokenFlag)
-	gitDir := cmd.String(gitDirFlag)
-	from := cmd.String(fromFlag)
 	target := cmd.String(targetFlag)
-	githubOrg := cmd.String(githubOrgFlag)
-	githubRepo := cmd.String(githubRepoFlag)
 	label := cmd.String(labelFlag)
 	numWorkers := cmd.Int(workersFlag)
 	dryRun := cmd.Bool(dryRunFlag)
+	journalPath := cmd.String(journalFlag)
+	syncLabels := cmd.Bool(syncFlag)
+
+	reportFormat, reportFile, err := parseReportFlags(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Validate number of workers
 	if numWorkers <= 0 {
-		log.Fatalf("Number of workers must be positive, got: %d", numWorkers)
+		return usageError(fmt.Errorf("number of workers must be positive, got: %d", numWorkers))
 	}
 
-	log.Println("Fetching git history in dir", gitDir, "for", from, "..", target)
+	client := github.NewClient(token)
 
-	commits := gitlog.GetHistory(gitDir, from, target)
+	references, from, err := collectReferences(cmd, client)
+	if err != nil {
+		return err
+	}
 
-	log.Println("Collection issue ids")
-	issueIds := gitlog.ExtractIssueIds(commits)
+	// the desired issues of a sync include those which are skipped on resume
+	desired := labelsync.NewDesired(references)
 
-	issueCount := len(issueIds)
+	outcomes := report.LabelOutcomes
+	if syncLabels {
+		outcomes = append(slices.Clone(outcomes), report.Removed)
+	}
+
+	// the journal identifies the run by the commits of the range, so that the entries of a
+	// moved tag are not resumed
+	gitDir := cmd.String(gitDirFlag)
+	fromCommit, err := gitlog.ResolveCommit(gitDir, from)
+	if err != nil {
+		return err
+	}
+	targetCommit, err := gitlog.ResolveCommit(gitDir, target)
+	if err != nil {
+		return err
+	}
+
+	run := journal.Run{Label: label, From: from, FromCommit: fromCommit, Target: target, TargetCommit: targetCommit}
+	runReport := report.New(label, from, target, outcomes)
+	if cmd.Bool(resumeFlag) {
+		completed, err := journal.Completed(journalPath, run)
+		if err != nil {
+			return err
+		}
+
+		var resumed []gitlog.IssueReference
+		references, resumed = skipCompleted(references, completed)
+		for _, reference := range resumed {
+			runReport.Add(newResult(reference, report.Resumed, nil))
+		}
+		log.Println("Resuming from journal", journalPath+",", "skipping", len(resumed), "issues processed by a previous run")
+	}
+
+	repositories := distinctRepositories(references)
+
+	log.Println("Fetching current labels of", len(references), "issues")
+	states, err := fetchLabelStates(client, references, label)
+	if err != nil {
+		return err
+	}
+
+	var pending []gitlog.IssueReference
+	counts := map[labelState]int{}
+	for i, reference := range references {
+		counts[states[i]]++
+		if states[i] == unlabeledState {
+			pending = append(pending, reference)
+		}
+	}
+	issueCount := len(pending)
 
 	if dryRun {
-		log.Println("[dry-run] Would add label", label, "to", issueCount, "issues in", githubOrg+"/"+githubRepo)
+		log.Println("[dry-run] Would add label", label, "to", issueCount, "issues in", strings.Join(repositories, ", "))
 	} else {
-		log.Println("Adding label", label, "to", issueCount, "issues in", githubOrg+"/"+githubRepo)
+		log.Println("Adding label", label, "to", issueCount, "issues in", strings.Join(repositories, ", "))
 	}
-	for _, id := range issueIds {
-		fmt.Printf("  https://github.com/%s/%s/issues/%d\n", githubOrg, githubRepo, id)
+	log.Println(counts[labeledState], "issues are already labeled,", counts[notFoundState], "issues were not found")
+	for i, reference := range references {
+		state := "new"
+		if states[i] != unlabeledState {
+			state = string(states[i])
+		}
+		fmt.Printf("  %s (%s, %s): %s\n", reference.URL(), reference.Commit.ShortSHA(), reference.Source, state)
 	}
 
-	client := github.NewClient(token)
-	client.EnsureLabelExists(githubOrg, githubRepo, label, dryRun)
+	var stale []gitlog.IssueReference
+	if syncLabels {
+		allowedRepositories, err := parseAllowedRepositories(cmd)
+		if err != nil {
+			return err
+		}
+
+		stale, err = staleReferences(client, allowedRepositories, label, desired)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			log.Println("[dry-run] Would remove label", label, "from", len(stale), "issues which are not referenced in the range")
+		} else {
+			log.Println("Removing label", label, "from", len(stale), "issues which are not referenced in the range")
+		}
+		for _, reference := range stale {
+			fmt.Printf("  %s: remove\n", reference.URL())
+		}
+	}
+
+	for _, repository := range repositories {
+		owner, repo, _ := gitlog.ParseRepository(repository)
+		if err := client.EnsureLabelExists(owner, repo, label, dryRun); err != nil {
+			return err
+		}
+	}
 
 	if dryRun {
 		return nil
 	}
 
-	log.Println("Updating", issueCount, "issues with", numWorkers, "workers")
-	bar := progress.NewProgressBar(issueCount)
+	runJournal, err := journal.Open(journalPath, run)
+	if err != nil {
+		return err
+	}
+	defer runJournal.Close()
 
-	addLabelsParallel(client, githubOrg, githubRepo, issueIds, label, bar, numWorkers)
+	for i, reference := range references {
+		var result report.Result
+		switch states[i] {
+		case labeledState:
+			result = newResult(reference, report.AlreadyLabeled, nil)
+		case notFoundState:
+			result = newResult(reference, report.Skipped, errIssueNotFound)
+		default:
+			continue
+		}
 
-	return nil
+		runReport.Add(result)
+		if err := runJournal.Record(result); err != nil {
+			return err
+		}
+	}
+
+	log.Println("Updating", issueCount+len(stale), "issues with", numWorkers, "workers, recording outcomes in", journalPath)
+	bar := progress.NewProgressBar(issueCount + len(stale))
+
+	labelErr := processParallel(pending, bar, numWorkers, runJournal, runReport, func(reference gitlog.IssueReference) (report.Outcome, error) {
+		// slow down the workers before the rate limit is exhausted
+		client.Throttle()
+		err := client.LabelIssue(reference.Owner, reference.Repo, reference.ID, label)
+		return labelOutcome(report.Labeled, err), err
+	})
+	removeErr := processParallel(stale, bar, numWorkers, runJournal, runReport, func(reference gitlog.IssueReference) (report.Outcome, error) {
+		client.Throttle()
+		err := client.RemoveLabel(reference.Owner, reference.Repo, reference.ID, label)
+		return labelOutcome(report.Removed, err), err
+	})
+	bar.Stop()
+
+	return errors.Join(labelErr, removeErr, finishReport(runReport, reportFormat, reportFile))
+}
+
+type labelState string
+
+const (
+	unlabeledState labelState = "not labeled"
+	labeledState   labelState = "already labeled"
+	notFoundState  labelState = "not found"
+)
+
+var errIssueNotFound = errors.New("issue or pull request not found")
+
+// fetchLabelStates looks up the current labels of the referenced issues and returns for
+// every reference whether the issue carries the label or does not exist. A repository
+// which does not exist or is not accessible fails the lookup, instead of reporting all
+// of its issues as not found.
+func fetchLabelStates(client *github.Client, references []gitlog.IssueReference, label string) ([]labelState, error) {
+	numbersByRepository := map[string][]int{}
+	for _, reference := range references {
+		numbersByRepository[reference.Repository()] = append(numbersByRepository[reference.Repository()], reference.ID)
+	}
+
+	labelsByRepository := make(map[string]map[int]github.IssueLabels, len(numbersByRepository))
+	for repository, numbers := range numbersByRepository {
+		owner, repo, _ := gitlog.ParseRepository(repository)
+		labels, err := client.FetchIssueLabels(owner, repo, numbers)
+		if err != nil {
+			return nil, err
+		}
+		labelsByRepository[repository] = labels
+	}
+
+	states := make([]labelState, len(references))
+	for i, reference := range references {
+		labels, found := labelsByRepository[reference.Repository()][reference.ID]
+		switch {
+		case !found:
+			states[i] = notFoundState
+		case labels.HasLabel(label):
+			states[i] = labeledState
+		default:
+			states[i] = unlabeledState
+		}
+	}
+	return states, nil
+}
+
+// collectCommits returns the commits of the git range given by the range flags, the
+// extractor for the configured reference rules and the start revision of the range with
+// 'auto' resolved to the previous release tag.
+func collectCommits(cmd *cli.Command) ([]gitlog.Commit, *gitlog.Extractor, string, error) {
+	gitDir := cmd.String(gitDirFlag)
+	from := cmd.String(fromFlag)
+	target := cmd.String(targetFlag)
+
+	historyMode, err := gitlog.ParseHistoryMode(cmd.String(historyFlag))
+	if err != nil {
+		return nil, nil, "", usageError(err)
+	}
+
+	extractor, err := createExtractor(cmd, cmd.String(githubOrgFlag), cmd.String(githubRepoFlag))
+	if err != nil {
+		return nil, nil, "", usageError(err)
+	}
+
+	if from == fromAuto {
+		from, err = gitlog.PreviousReleaseTag(gitDir, target)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		log.Println("Detected previous release", from, "of", target)
+	}
+
+	log.Println("Fetching git history in dir", gitDir, "for", from, "..", target)
+
+	commits, err := gitlog.GetHistory(gitDir, from, target, gitlog.HistoryOptions{
+		Mode:         historyMode,
+		IncludePaths: cmd.StringSlice(includePathFlag),
+		ExcludePaths: cmd.StringSlice(excludePathFlag),
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return commits, extractor, from, nil
+}
+
+// staleReferences lists the issues of the repositories which carry the label but are not
+// in the desired set of issues.
+func staleReferences(client *github.Client, repositories []string, label string, desired labelsync.Desired) ([]gitlog.IssueReference, error) {
+	var stale []gitlog.IssueReference
+	for _, repository := range repositories {
+		owner, repo, _ := gitlog.ParseRepository(repository)
+
+		log.Println("Fetching issues with label", label, "in", repository)
+		issues, err := client.ListIssues(owner, repo, label)
+		if err != nil {
+			return nil, err
+		}
+
+		stale = append(stale, desired.Stale(owner, repo, issues)...)
+	}
+	return stale, nil
+}
+
+// skipCompleted splits the references into those which still have to be processed and
+// those which were completed by a previous run.
+func skipCompleted(references []gitlog.IssueReference, completed map[string]bool) ([]gitlog.IssueReference, []gitlog.IssueReference) {
+	var remaining, skipped []gitlog.IssueReference
+	for _, reference := range references {
+		if completed[reference.Key()] {
+			skipped = append(skipped, reference)
+		} else {
+			remaining = append(remaining, reference)
+		}
+	}
+	return remaining, skipped
+}
+
+// collectReferences returns the issue references of the commits in the git range given
+// by the range flags, and the start revision of the range with 'auto' resolved to the
+// previous release tag.
+func collectReferences(cmd *cli.Command, client *github.Client) ([]gitlog.IssueReference, string, error) {
+	githubOrg := cmd.String(githubOrgFlag)
+	githubRepo := cmd.String(githubRepoFlag)
+
+	allowedRepositories, err := parseAllowedRepositories(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	commits, extractor, from, err := collectCommits(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Println("Collection issue ids")
+	references := extractor.ExtractIssueIds(commits)
+
+	if cmd.Bool(resolvePRsFlag) {
+		log.Println("Resolving pull requests and closing issues of", len(commits), "commits")
+		resolved, err := resolveClosingIssues(client, githubOrg, githubRepo, commits)
+		if err != nil {
+			return nil, "", err
+		}
+		references = gitlog.AppendUnique(references, resolved...)
+	}
+
+	if cmd.Bool(scanPRBodiesFlag) {
+		log.Println("Scanning pull request descriptions for issue ids")
+		scanned, err := scanPullRequests(client, extractor, githubOrg, githubRepo, commits)
+		if err != nil {
+			return nil, "", err
+		}
+		references = gitlog.AppendUnique(references, scanned...)
+	}
+
+	references, rejected := filterAllowedRepositories(references, allowedRepositories)
+	for _, reference := range rejected {
+		log.Printf("Warning: Skipping %s referenced by %s, repository %s is not allowed\n", reference, reference.Commit.ShortSHA(), reference.Repository())
+	}
+
+	return references, from, nil
+}
+
+// resolveClosingIssues returns the merged pull requests of the commits and the issues
+// they close as references of the commits.
+func resolveClosingIssues(client *github.Client, githubOrg, githubRepo string, commits []gitlog.Commit) ([]gitlog.IssueReference, error) {
+	commitsBySHA := make(map[string]*gitlog.Commit, len(commits))
+	shas := make([]string, 0, len(commits))
+	for i := range commits {
+		commitsBySHA[commits[i].SHA] = &commits[i]
+		shas = append(shas, commits[i].SHA)
+	}
+
+	closingIssues, err := client.ResolveClosingIssues(githubOrg, githubRepo, shas)
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]gitlog.IssueReference, 0, len(closingIssues))
+	for _, issue := range closingIssues {
+		references = append(references, gitlog.IssueReference{
+			Owner:  issue.Owner,
+			Repo:   issue.Repo,
+			ID:     issue.Number,
+			Commit: commitsBySHA[issue.SHA],
+			Source: gitlog.ClosingIssueSource,
+		})
+	}
+	return references, nil
+}
+
+// scanPullRequests returns the issues referenced in the title and body of the pull
+// requests merged by the commits.
+func scanPullRequests(client *github.Client, extractor *gitlog.Extractor, githubOrg, githubRepo string, commits []gitlog.Commit) ([]gitlog.IssueReference, error) {
+	commitsByPullRequest := map[int]*gitlog.Commit{}
+	var numbers []int
+	for i := range commits {
+		if number, ok := commits[i].PullRequest(); ok && commitsByPullRequest[number] == nil {
+			commitsByPullRequest[number] = &commits[i]
+			numbers = append(numbers, number)
+		}
+	}
+
+	pullRequests, err := client.FetchPullRequests(githubOrg, githubRepo, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	var references []gitlog.IssueReference
+	for _, pullRequest := range pullRequests {
+		for _, reference := range extractor.ExtractFromText(pullRequest.Title + "\n" + pullRequest.Body) {
+			reference.Commit = commitsByPullRequest[pullRequest.Number]
+			reference.Source = gitlog.PullRequestSource
+			references = gitlog.AppendUnique(references, reference)
+		}
+	}
+	return references, nil
+}
+
+// parseAllowedRepositories returns the repositories in which issues may be labeled, by
+// default the GitHub repository given by --org and --repo.
+func parseAllowedRepositories(cmd *cli.Command) ([]string, error) {
+	repositories := cmd.StringSlice(allowedRepoFlag)
+	if len(repositories) == 0 {
+		repositories = []string{cmd.String(githubOrgFlag) + "/" + cmd.String(githubRepoFlag)}
+	}
+	for _, repository := range repositories {
+		if _, _, err := gitlog.ParseRepository(repository); err != nil {
+			return nil, usageError(err)
+		}
+	}
+	return repositories, nil
+}
+
+// filterAllowedRepositories splits the references into those which belong to one of
+// the allowed repositories and those which must not be touched.
+func filterAllowedRepositories(references []gitlog.IssueReference, allowedRepositories []string) ([]gitlog.IssueReference, []gitlog.IssueReference) {
+	var allowed, rejected []gitlog.IssueReference
+	for _, reference := range references {
+		if slices.ContainsFunc(allowedRepositories, func(repository string) bool {
+			return strings.EqualFold(repository, reference.Repository())
+		}) {
+			allowed = append(allowed, reference)
+		} else {
+			rejected = append(rejected, reference)
+		}
+	}
+	return allowed, rejected
+}
+
+// distinctRepositories returns the repositories of the references in order of their
+// first appearance.
+func distinctRepositories(references []gitlog.IssueReference) []string {
+	var repositories []string
+	for _, reference := range references {
+		if !slices.Contains(repositories, reference.Repository()) {
+			repositories = append(repositories, reference.Repository())
+		}
+	}
+	return repositories
+}
+
+// defaultReferenceRepositories are recognized besides the GitHub repository if no
+// repositories are configured. The main repository was renamed from camunda/zeebe to
+// camunda/camunda and commit messages use both names.
+var defaultReferenceRepositories = []string{"camunda/camunda", "camunda/zeebe"}
+
+// renamedRepositories are previous names of the GitHub repository, whose references
+// belong to --org/--repo if no aliases are configured.
+var renamedRepositories = []string{"camunda/zeebe"}
+
+// createExtractor builds the issue reference rules from the reference config file,
+// overridden by the reference flags. Without any configured repository the references
+// to the given GitHub repository and the default reference repositories are recognized,
+// without any configured alias the renamed repositories are resolved to it.
+func createExtractor(cmd *cli.Command, githubOrg, githubRepo string) (*gitlog.Extractor, error) {
+	var rules gitlog.ReferenceRules
+	if path := cmd.String(referenceConfigFlag); path != "" {
+		var err error
+		rules, err = gitlog.LoadReferenceRules(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keywords := cmd.StringSlice(referenceKeywordFlag); len(keywords) > 0 {
+		rules.Keywords = keywords
+	}
+	if repositories := cmd.StringSlice(referenceRepoFlag); len(repositories) > 0 {
+		rules.Repositories = repositories
+	}
+	if patterns := cmd.StringSlice(referencePatternFlag); len(patterns) > 0 {
+		rules.Patterns = patterns
+	}
+	if aliases := cmd.StringSlice(referenceAliasFlag); len(aliases) > 0 {
+		rules.Aliases = map[string]string{}
+		for _, alias := range aliases {
+			previous, current, found := strings.Cut(alias, "=")
+			if !found {
+				return nil, fmt.Errorf("invalid reference alias %q, expected old-owner/old-repo=owner/repo", alias)
+			}
+			rules.Aliases[previous] = current
+		}
+	}
+	if len(rules.Aliases) == 0 {
+		rules.Aliases = map[string]string{}
+		for _, repository := range renamedRepositories {
+			if !strings.EqualFold(repository, githubOrg+"/"+githubRepo) {
+				rules.Aliases[repository] = githubOrg + "/" + githubRepo
+			}
+		}
+	}
+	if len(rules.Repositories) == 0 {
+		rules.Repositories = []string{githubOrg + "/" + githubRepo}
+		for _, repository := range defaultReferenceRepositories {
+			if !slices.ContainsFunc(rules.Repositories, func(configured string) bool {
+				return strings.EqualFold(configured, repository)
+			}) {
+				rules.Repositories = append(rules.Repositories, repository)
+			}
+		}
+	}
+
+	return gitlog.NewExtractor(githubOrg, githubRepo, rules)
 }
 
 func generateChangelog(_ context.Context, cmd *cli.Command) error {
@@ -236,13 +1202,100 @@ func generateChangelog(_ context.Context, cmd *cli.Command) error {
 	githubRepo := cmd.String(githubRepoFlag)
 	label := cmd.String(labelFlag)
 
+	format := cmd.String(formatFlag)
+	if format != markdownFormat && format != jsonFormat {
+		return usageError(fmt.Errorf("unknown format %q, expected one of: %s, %s", format, markdownFormat, jsonFormat))
+	}
+	if format == jsonFormat && cmd.String(templateFlag) != "" {
+		return usageError(fmt.Errorf("--%s cannot be combined with --%s %s", templateFlag, formatFlag, jsonFormat))
+	}
+
+	output := cmd.String(outputFlag)
+	insert := cmd.Bool(insertFlag)
+	marker := cmd.String(markerFlag)
+	if insert && output == "" {
+		return usageError(fmt.Errorf("--%s requires --%s", insertFlag, outputFlag))
+	}
+	if insert && format == jsonFormat {
+		return usageError(fmt.Errorf("--%s cannot be combined with --%s %s", insertFlag, formatFlag, jsonFormat))
+	}
+	if insert && strings.TrimSpace(marker) == "" {
+		return usageError(fmt.Errorf("--%s must not be empty", markerFlag))
+	}
+
+	categories, tmpl, err := parseChangelogFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	client := github.NewClient(token)
 
 	log.Println("Fetching issues for GitHub label", label)
-	changelog := client.FetchIssues(githubOrg, githubRepo, label)
+	changelog, err := client.FetchChangelog(githubOrg, githubRepo, label, categories)
+	if err != nil {
+		return err
+	}
 
 	log.Println("Generating changelog for GitHub label", label)
-	fmt.Println(changelog.String())
+	content, err := renderChangelog(changelog, format, tmpl)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return writeChangelog(output, insert, marker, label, content)
+}
+
+// writeChangelog prints the changelog, or writes it to the output file.
+func writeChangelog(output string, insert bool, marker, label string, content []byte) error {
+	switch {
+	case output == "":
+		fmt.Println(string(content))
+		return nil
+	case insert:
+		log.Println("Inserting changelog into", output)
+		return changelogfile.InsertFile(output, marker, label, content)
+	default:
+		log.Println("Writing changelog to", output)
+		if !bytes.HasSuffix(content, []byte("\n")) {
+			content = append(content, '\n')
+		}
+		return changelogfile.WriteFile(output, content, 0o644)
+	}
+}
+
+// parseChangelogFlags loads the categories and the template of the changelog, or
+// returns the defaults.
+func parseChangelogFlags(cmd *cli.Command) (github.Categories, *template.Template, error) {
+	categories := github.DefaultCategories()
+	if path := cmd.String(categoriesFlag); path != "" {
+		var err error
+		categories, err = github.LoadCategories(path)
+		if err != nil {
+			return categories, nil, usageError(err)
+		}
+	}
+
+	tmpl := github.DefaultTemplate()
+	if path := cmd.String(templateFlag); path != "" {
+		var err error
+		tmpl, err = github.ParseTemplate(path)
+		if err != nil {
+			return categories, nil, usageError(err)
+		}
+	}
+
+	return categories, tmpl, nil
+}
+
+// renderChangelog renders the changelog with the template, or as JSON document.
+func renderChangelog(changelog *github.Changelog, format string, tmpl *template.Template) ([]byte, error) {
+	if format == jsonFormat {
+		return changelog.JSON()
+	}
+
+	var b bytes.Buffer
+	if err := changelog.Render(&b, tmpl); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
 }
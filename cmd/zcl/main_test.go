@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/journal"
+	"github.com/camunda/zeebe-changelog/pkg/labelsync"
+	"github.com/camunda/zeebe-changelog/pkg/report"
+	gogithub "github.com/google/go-github/v83/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+// extractReferences extracts the references of a commit with the given message, using
+// the extractor add-labels creates for the given arguments. The references are split
+// by the allow-list of add-labels.
+func extractReferences(t *testing.T, message string, args ...string) (allowed []string, rejected []string) {
+	t.Helper()
+
+	app := createApp()
+	for _, command := range app.Commands {
+		if command.Name != "add-labels" {
+			continue
+		}
+		command.Action = func(_ context.Context, cmd *cli.Command) error {
+			extractor, err := createExtractor(cmd, cmd.String(githubOrgFlag), cmd.String(githubRepoFlag))
+			if err != nil {
+				return err
+			}
+			allowedRepositories, err := parseAllowedRepositories(cmd)
+			if err != nil {
+				return err
+			}
+
+			references := extractor.ExtractIssueIds([]gitlog.Commit{{SHA: "abc", Parents: []string{"p1", "p2"}, Subject: message}})
+			allowedReferences, rejectedReferences := filterAllowedRepositories(references, allowedRepositories)
+			for _, reference := range allowedReferences {
+				allowed = append(allowed, reference.String())
+			}
+			for _, reference := range rejectedReferences {
+				rejected = append(rejected, reference.String())
+			}
+			return nil
+		}
+	}
+
+	// a git dir without configuration file, so that no profile applies
+	args = append([]string{"zcl", "add-labels", "--token", "t", "--from", "a", "--target", "b", "--label", "l", "--gitDir", t.TempDir()}, args...)
+	if err := app.Run(context.Background(), args); err != nil {
+		t.Fatalf("run add-labels: %v", err)
+	}
+	return allowed, rejected
+}
+
+func TestCreateExtractor_DefaultFlags(t *testing.T) {
+	allowed, rejected := extractReferences(t, "closes camunda/zeebe#5678\nbackport https://github.com/camunda/zeebe/5555\nrelated camunda/camunda#1 and camunda/connectors#2")
+
+	assert.Equal(t, []string{"camunda/camunda#5678", "camunda/camunda#5555", "camunda/camunda#1"}, allowed)
+	assert.Equal(t, []string{"camunda/connectors#2"}, rejected)
+}
+
+func TestCreateExtractor_Aliases(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"Renamed repository of another target": {
+			args:     []string{"--org", "zeebe-io", "--repo", "zeebe"},
+			expected: []string{"zeebe-io/zeebe#1", "zeebe-io/zeebe#2"},
+		},
+		"Renamed repository is the target": {
+			args:     []string{"--org", "camunda", "--repo", "zeebe"},
+			expected: []string{"camunda/zeebe#1", "camunda/zeebe#2"},
+		},
+		"Configured aliases replace the defaults": {
+			args:     []string{"--reference-alias", "camunda/tasklist=camunda/camunda", "--allowed-repo", "camunda/camunda", "--allowed-repo", "camunda/zeebe"},
+			expected: []string{"camunda/zeebe#1", "camunda/zeebe#2", "camunda/camunda#3"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			allowed, _ := extractReferences(t, "closes camunda/zeebe#1, https://github.com/camunda/zeebe/2 and camunda/tasklist#3", tc.args...)
+			assert.Equal(t, tc.expected, allowed)
+		})
+	}
+}
+
+func TestCreateExtractor_DefaultRepositories(t *testing.T) {
+	tests := map[string]struct {
+		message  string
+		args     []string
+		expected []string
+	}{
+		"Short reference": {
+			message:  "closes #1",
+			expected: []string{"camunda/camunda#1"},
+		},
+		"Main repository shorthand": {
+			message:  "closes camunda/camunda/2",
+			expected: []string{"camunda/camunda#2"},
+		},
+		"Main repository URL": {
+			message:  "closes https://github.com/camunda/camunda/3",
+			expected: []string{"camunda/camunda#3"},
+		},
+		"Main repository URL of another target": {
+			message:  "closes https://github.com/camunda/camunda/4",
+			args:     []string{"--org", "zeebe-io", "--repo", "zeebe", "--allowed-repo", "camunda/camunda"},
+			expected: []string{"camunda/camunda#4"},
+		},
+		"Configured repositories replace the defaults": {
+			message:  "closes camunda/camunda/5 and camunda/connectors/6",
+			args:     []string{"--reference-repo", "camunda/connectors", "--allowed-repo", "camunda/connectors"},
+			expected: []string{"camunda/connectors#6"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			allowed, _ := extractReferences(t, tc.message, tc.args...)
+			assert.Equal(t, tc.expected, allowed)
+		})
+	}
+}
+
+func TestSkipCompleted_ResumedIssuesStayDesired(t *testing.T) {
+	// issue 1 was labeled by an interrupted run and is skipped on resume, but it is still
+	// referenced in the range and must keep the label
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	run := journal.Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+	previous, err := journal.Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, previous.Record(report.Result{Repository: "Camunda/Camunda", Issue: 1, Outcome: report.Labeled}))
+	assert.NoError(t, previous.Close())
+
+	completed, err := journal.Completed(path, run)
+	assert.NoError(t, err)
+
+	references := []gitlog.IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 1},
+		{Owner: "camunda", Repo: "camunda", ID: 2},
+	}
+	desired := labelsync.NewDesired(references)
+	remaining, resumed := skipCompleted(references, completed)
+
+	assert.Equal(t, references[1:], remaining)
+	assert.Equal(t, references[:1], resumed)
+
+	labeled := []*github.Issue{
+		github.NewIssue(&gogithub.Issue{Number: gogithub.Ptr(1)}),
+		github.NewIssue(&gogithub.Issue{Number: gogithub.Ptr(2)}),
+	}
+	assert.Empty(t, desired.Stale("camunda", "camunda", labeled))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/camunda/zeebe-changelog/pkg/config"
+	"github.com/urfave/cli/v3"
+)
+
+// targetPlaceholder in the label is replaced with the target, or the tag for commands
+// without target, e.g. "version:{target}".
+const targetPlaceholder = "{target}"
+
+// profileFlagsKey is the context key of the flags which were set by the profile.
+type profileFlagsKey struct{}
+
+// setByProfile reports whether all of the given flags which are set were set by the
+// profile, and at least one of them is set.
+func setByProfile(ctx context.Context, cmd *cli.Command, names ...string) bool {
+	profileFlags, _ := ctx.Value(profileFlagsKey{}).(map[string]bool)
+
+	set := false
+	for _, name := range names {
+		if !cmd.IsSet(name) {
+			continue
+		}
+		if !profileFlags[name] {
+			return false
+		}
+		set = true
+	}
+	return set
+}
+
+// applyProfile sets the flags of the command which are neither given on the command line
+// nor by environment variable to the values of the selected profile of the configuration
+// file. It runs before the required flags are checked, so that profiles can provide them.
+func applyProfile(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	profile := cmd.String(profileFlag)
+
+	path := cmd.String(configFlag)
+	if path == "" {
+		dir := "."
+		if gitDir := cmd.String(gitDirFlag); gitDir != "" {
+			dir = gitDir
+		}
+
+		var found bool
+		if path, found = config.Discover(dir); !found {
+			if profile != "" {
+				return ctx, usageError(fmt.Errorf("profile %q selected, but no %s found in the root of the git working tree", profile, config.FileName))
+			}
+			return ctx, expandLabel(cmd)
+		}
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return ctx, usageError(err)
+	}
+	values, err := cfg.Profile(profile)
+	if err != nil {
+		return ctx, usageError(fmt.Errorf("%s: %w", path, err))
+	}
+	if profile == "" {
+		profile = cfg.Default
+	}
+
+	known := flagNames(cmd.Root())
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	profileFlags := map[string]bool{}
+	for _, key := range keys {
+		if !known[key] {
+			return ctx, usageError(fmt.Errorf("%s: unknown flag %q in profile %q", path, key, profile))
+		}
+		// flags of other commands are ignored, as are flags given explicitly
+		if !hasFlag(cmd, key) || cmd.IsSet(key) {
+			continue
+		}
+
+		for _, value := range values[key] {
+			if err := cmd.Set(key, value); err != nil {
+				return ctx, usageError(fmt.Errorf("%s: invalid value %q of %s in profile %q: %w", path, value, key, profile, err))
+			}
+		}
+		profileFlags[key] = true
+	}
+	if profile != "" {
+		log.Printf("Using profile %q of %s\n", profile, path)
+	}
+
+	return context.WithValue(ctx, profileFlagsKey{}, profileFlags), expandLabel(cmd)
+}
+
+// expandLabel replaces the target placeholder in the label.
+func expandLabel(cmd *cli.Command) error {
+	label := cmd.String(labelFlag)
+	if !strings.Contains(label, targetPlaceholder) {
+		return nil
+	}
+
+	target := cmd.String(targetFlag)
+	if target == "" {
+		target = cmd.String(tagFlag)
+	}
+	if target == "" {
+		return usageError(fmt.Errorf("label %q requires --%s or --%s to replace %s", label, targetFlag, tagFlag, targetPlaceholder))
+	}
+
+	return cmd.Set(labelFlag, strings.ReplaceAll(label, targetPlaceholder, target))
+}
+
+func hasFlag(cmd *cli.Command, name string) bool {
+	for _, flag := range cmd.Flags {
+		if slices.Contains(flag.Names(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagNames returns the names of the flags of all commands.
+func flagNames(root *cli.Command) map[string]bool {
+	names := map[string]bool{}
+	for _, command := range append([]*cli.Command{root}, root.Commands...) {
+		for _, flag := range command.Flags {
+			for _, name := range flag.Names() {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
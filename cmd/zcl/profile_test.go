@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+const testConfig = `default: camunda
+profiles:
+  camunda:
+    org: profile-org
+    repo: profile-repo
+    label: "version:{target}"
+    from: 8.5.0
+    target: 8.6.0
+    workers: 3
+  tag:
+    label: "release:{target}"
+`
+
+// runCommand runs the command of the app with the given arguments, replacing its action
+// with the given one.
+func runCommand(t *testing.T, name string, action cli.ActionFunc, args ...string) error {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".zcl.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	app := createApp()
+	app.Writer = io.Discard
+	app.ErrWriter = io.Discard
+	for _, command := range app.Commands {
+		if command.Name == name {
+			command.Action = action
+		}
+	}
+
+	return app.Run(context.Background(), append([]string{"zcl", "--config", path, name}, args...))
+}
+
+func TestApplyProfile_Precedence(t *testing.T) {
+	t.Setenv(githubRepoEnv, "env-repo")
+	t.Setenv(workersEnv, "7")
+
+	var org, repo, history string
+	var workers int
+	err := runCommand(t, "add-labels", func(_ context.Context, cmd *cli.Command) error {
+		org = cmd.String(githubOrgFlag)
+		repo = cmd.String(githubRepoFlag)
+		workers = cmd.Int(workersFlag)
+		history = cmd.String(historyFlag)
+		return nil
+	}, "--token", "t", "--workers", "5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "profile-org", org, "profile overrides the default")
+	assert.Equal(t, "env-repo", repo, "environment overrides the profile")
+	assert.Equal(t, 5, workers, "flag overrides environment and profile")
+	assert.Equal(t, string(gitlog.MergesMode), history, "default applies without profile value")
+}
+
+func TestApplyProfile_ExpandsTargetInLabel(t *testing.T) {
+	tests := map[string]struct {
+		command  string
+		args     []string
+		expected string
+	}{
+		"Target of the profile": {command: "add-labels", args: []string{"--token", "t"}, expected: "version:8.6.0"},
+		"Explicit target":       {command: "add-labels", args: []string{"--token", "t", "--target", "8.6.1"}, expected: "version:8.6.1"},
+		"Explicit label":        {command: "add-labels", args: []string{"--token", "t", "--label", "v:{target}"}, expected: "v:8.6.0"},
+		"Tag without target":    {command: "publish", args: []string{"--token", "t", "--profile", "tag", "--tag", "8.6.2"}, expected: "release:8.6.2"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var label string
+			err := runCommand(t, tc.command, func(_ context.Context, cmd *cli.Command) error {
+				label = cmd.String(labelFlag)
+				return nil
+			}, tc.args...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, label)
+		})
+	}
+}
+
+func TestApplyProfile_UnknownProfile(t *testing.T) {
+	err := runCommand(t, "add-labels", func(context.Context, *cli.Command) error { return nil }, "--token", "t", "--profile", "unknown")
+
+	assert.Error(t, err)
+	assert.Equal(t, exitCodeInvalidUsage, exitCode(err))
+}
+
+func TestSelectIssues_ProfileRange(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected issueSelection
+		err      string
+	}{
+		"Range of the profile": {
+			expected: issueSelection{from: "8.5.0", target: "8.6.0", issues: []string{}},
+		},
+		"Issues override the range of the profile": {
+			args:     []string{"--issues", "1"},
+			expected: issueSelection{issues: []string{"1"}},
+		},
+		"All overrides the range of the profile": {
+			args:     []string{"--all"},
+			expected: issueSelection{issues: []string{}, all: true},
+		},
+		"Explicit range conflicts with issues": {
+			args: []string{"--issues", "1", "--target", "8.6.1"},
+			err:  "expected exactly one of",
+		},
+		"Explicit issues conflict with all": {
+			args: []string{"--issues", "1", "--all"},
+			err:  "expected exactly one of",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var selection issueSelection
+			err := runCommand(t, "remove-labels", func(ctx context.Context, cmd *cli.Command) error {
+				var err error
+				selection, err = selectIssues(ctx, cmd)
+				return err
+			}, append([]string{"--token", "t", "--label", "version:8.6.0"}, tc.args...)...)
+
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				assert.Equal(t, exitCodeInvalidUsage, exitCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, selection)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/urfave/cli/v3"
+)
+
+func publishReleaseNotes(_ context.Context, cmd *cli.Command) error {
+	token := cmd.String(gitApiTokenFlag)
+	githubOrg := cmd.String(githubOrgFlag)
+	githubRepo := cmd.String(githubRepoFlag)
+	label := cmd.String(labelFlag)
+	tag := cmd.String(tagFlag)
+	dryRun := cmd.Bool(dryRunFlag)
+
+	categories, tmpl, err := parseChangelogFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient(token)
+
+	log.Println("Fetching issues for GitHub label", label)
+	changelog, err := client.FetchChangelog(githubOrg, githubRepo, label, categories)
+	if err != nil {
+		return err
+	}
+
+	body, err := renderChangelog(changelog, markdownFormat, tmpl)
+	if err != nil {
+		return err
+	}
+
+	return publishChangelog(client, githubOrg, githubRepo, tag, string(body), dryRun, os.Stdout)
+}
+
+// publishChangelog writes the body to the draft release of the tag. In dry-run mode the
+// difference to the current release notes is printed to out instead.
+func publishChangelog(client *github.Client, githubOrg, githubRepo, tag, body string, dryRun bool, out io.Writer) error {
+	if !dryRun {
+		log.Println("Publishing changelog to the draft release of", tag, "in", githubOrg+"/"+githubRepo)
+		release, err := client.PublishReleaseNotes(githubOrg, githubRepo, tag, body)
+		if err != nil {
+			return err
+		}
+		log.Println("Published changelog to", release.URL)
+		return nil
+	}
+
+	release, err := client.FindRelease(githubOrg, githubRepo, tag)
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	switch {
+	case release == nil:
+		log.Println("[dry-run] Would create a draft release of", tag, "in", githubOrg+"/"+githubRepo)
+	case !release.Draft:
+		return fmt.Errorf("%w: %s", github.ErrReleasePublished, release.URL)
+	default:
+		log.Println("[dry-run] Would update the draft release", release.URL)
+		current = release.Body
+	}
+
+	diff, err := releaseDiff(tag, current, body)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		log.Println("[dry-run] The release notes are up to date")
+		return nil
+	}
+	_, err = fmt.Fprint(out, diff)
+	return err
+}
+
+// releaseDiff returns a unified diff of the current and the new release notes. Line
+// endings are normalized like PublishReleaseNotes compares the notes.
+func releaseDiff(tag, current, updated string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(github.NormalizeLineEndings(current)),
+		B:        difflib.SplitLines(github.NormalizeLineEndings(updated)),
+		FromFile: tag + " (current)",
+		ToFile:   tag + " (generated)",
+		Context:  3,
+	})
+}
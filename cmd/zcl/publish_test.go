@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseDiff_IgnoresLineEndings(t *testing.T) {
+	diff, err := releaseDiff("8.6.0", "# Notes\r\n\r\n* fix\r\n", "# Notes\n\n* fix\n")
+
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestReleaseDiff(t *testing.T) {
+	diff, err := releaseDiff("8.6.0", "# Notes\r\n* fix\r\n", "# Notes\n* fix\n* feature\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "--- 8.6.0 (current)\n+++ 8.6.0 (generated)\n@@ -1,3 +1,4 @@\n # Notes\n * fix\n+* feature\n \n", diff)
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/pipeline"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	labelStep    = "label"
+	verifyStep   = "verify"
+	generateStep = "generate"
+	publishStep  = "publish"
+
+	verifyTimeoutDefault = 5 * time.Minute
+	verifyInterval       = 15 * time.Second
+)
+
+var releaseSteps = []string{labelStep, verifyStep, generateStep, publishStep}
+
+func releaseChangelog(ctx context.Context, cmd *cli.Command) error {
+	token := cmd.String(gitApiTokenFlag)
+	githubOrg := cmd.String(githubOrgFlag)
+	githubRepo := cmd.String(githubRepoFlag)
+	target := cmd.String(targetFlag)
+	dryRun := cmd.Bool(dryRunFlag)
+	output := cmd.String(outputFlag)
+	insert := cmd.Bool(insertFlag)
+	marker := cmd.String(markerFlag)
+
+	label := cmd.String(labelFlag)
+	if label == "" {
+		// the label step reads the label from the flags
+		label = "version:" + target
+		if err := cmd.Set(labelFlag, label); err != nil {
+			return err
+		}
+	}
+	tag := cmd.String(tagFlag)
+	if tag == "" {
+		tag = target
+	}
+
+	skip := map[string]bool{}
+	for _, step := range cmd.StringSlice(skipFlag) {
+		if !slices.Contains(releaseSteps, step) {
+			return usageError(fmt.Errorf("unknown step %q, expected one of: %s", step, strings.Join(releaseSteps, ", ")))
+		}
+		skip[step] = true
+	}
+	if insert && output == "" {
+		return usageError(fmt.Errorf("--%s requires --%s", insertFlag, outputFlag))
+	}
+
+	categories, tmpl, err := parseChangelogFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	// the run is identified by the resolved range, so that a new or moved release tag
+	// does not resume the state of the old range
+	gitDir := cmd.String(gitDirFlag)
+	from := cmd.String(fromFlag)
+	if from == fromAuto {
+		from, err = gitlog.PreviousReleaseTag(gitDir, target)
+		if err != nil {
+			return err
+		}
+		log.Println("Detected previous release", from, "of", target)
+		if err := cmd.Set(fromFlag, from); err != nil {
+			return err
+		}
+	}
+	fromCommit, err := gitlog.ResolveCommit(gitDir, from)
+	if err != nil {
+		return err
+	}
+	targetCommit, err := gitlog.ResolveCommit(gitDir, target)
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient(token)
+
+	// the changelog is rendered once for the generate and publish step
+	var content []byte
+	renderOnce := func() ([]byte, error) {
+		if content != nil {
+			return content, nil
+		}
+
+		log.Println("Fetching issues for GitHub label", label)
+		changelog, err := client.FetchChangelog(githubOrg, githubRepo, label, categories)
+		if err != nil {
+			return nil, err
+		}
+		content, err = renderChangelog(changelog, markdownFormat, tmpl)
+		return content, err
+	}
+
+	steps := []pipeline.Step{
+		{Name: labelStep, Run: func(retry bool) error {
+			if retry {
+				// skip the issues labeled by the failed attempt
+				if err := cmd.Set(resumeFlag, "true"); err != nil {
+					return err
+				}
+			}
+			return addLabels(ctx, cmd)
+		}},
+		{Name: verifyStep, Run: func(bool) error {
+			if dryRun {
+				log.Println("[dry-run] Would verify that GitHub lists all labeled issues with label", label)
+				return nil
+			}
+			return verifyLabels(cmd, client, newLabelVerifier(client), label, cmd.Duration(verifyTimeoutFlag))
+		}},
+		{Name: generateStep, Run: func(bool) error {
+			content, err := renderOnce()
+			if err != nil {
+				return err
+			}
+			if dryRun && output != "" {
+				log.Println("[dry-run] Would write changelog to", output)
+				output = ""
+			}
+			return writeChangelog(output, insert, marker, label, content)
+		}},
+		{Name: publishStep, Run: func(bool) error {
+			content, err := renderOnce()
+			if err != nil {
+				return err
+			}
+			return publishChangelog(client, githubOrg, githubRepo, tag, string(content), dryRun, os.Stdout)
+		}},
+	}
+
+	// dry runs change nothing and are neither recorded nor resumed
+	statePath := cmd.String(stateFlag)
+	if dryRun {
+		statePath = ""
+	}
+
+	run := pipeline.Run{
+		Repository:   githubOrg + "/" + githubRepo,
+		Label:        label,
+		From:         from,
+		FromCommit:   fromCommit,
+		Target:       target,
+		TargetCommit: targetCommit,
+	}
+	if err := pipeline.New(statePath, run, steps...).Execute(skip); err != nil {
+		return err
+	}
+
+	log.Println("Release", tag, "completed")
+	return nil
+}
+
+// labelVerifier waits for the issue listing of GitHub to contain labeled issues. The
+// listing, sleep and clock are replaced in tests.
+type labelVerifier struct {
+	listIssues func(githubOrg, githubRepo, label string) ([]*github.Issue, error)
+	sleep      func(time.Duration)
+	now        func() time.Time
+}
+
+func newLabelVerifier(client *github.Client) *labelVerifier {
+	return &labelVerifier{listIssues: client.ListIssues, sleep: time.Sleep, now: time.Now}
+}
+
+// verifyLabels waits until GitHub lists all issues of the range which carry the label,
+// since the listing generate is based on may lag behind labeling.
+func verifyLabels(cmd *cli.Command, client *github.Client, verifier *labelVerifier, label string, timeout time.Duration) error {
+	references, _, err := collectReferences(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	states, err := fetchLabelStates(client, references, label)
+	if err != nil {
+		return err
+	}
+
+	var labeled []gitlog.IssueReference
+	unlabeled := 0
+	for i, reference := range references {
+		switch states[i] {
+		case labeledState:
+			labeled = append(labeled, reference)
+		case unlabeledState:
+			unlabeled++
+		}
+	}
+	if unlabeled > 0 {
+		log.Println("Warning:", unlabeled, "issues referenced in the range do not carry the label", label)
+	}
+
+	return verifier.waitUntilListed(labeled, label, timeout)
+}
+
+// waitUntilListed polls the issue listing until it contains all labeled issues or the
+// timeout expires.
+func (v *labelVerifier) waitUntilListed(labeled []gitlog.IssueReference, label string, timeout time.Duration) error {
+	deadline := v.now().Add(timeout)
+	for {
+		missing, err := v.unlistedIssues(labeled, label)
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			log.Println("GitHub lists all", len(labeled), "labeled issues with label", label)
+			return nil
+		}
+		if v.now().After(deadline) {
+			return fmt.Errorf("GitHub does not list %d labeled issues with label %s after %s, e.g. %s", len(missing), label, timeout, missing[0].URL())
+		}
+
+		log.Println("Waiting for GitHub to list", len(missing), "labeled issues with label", label)
+		v.sleep(verifyInterval)
+	}
+}
+
+// unlistedIssues returns the labeled issues which are missing in the issue listing of
+// their repository for the label.
+func (v *labelVerifier) unlistedIssues(labeled []gitlog.IssueReference, label string) ([]gitlog.IssueReference, error) {
+	listed := map[string]bool{}
+	for _, repository := range distinctRepositories(labeled) {
+		owner, repo, _ := gitlog.ParseRepository(repository)
+
+		issues, err := v.listIssues(owner, repo, label)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			listed[gitlog.IssueReference{Owner: owner, Repo: repo, ID: issue.Number()}.Key()] = true
+		}
+	}
+
+	var missing []gitlog.IssueReference
+	for _, reference := range labeled {
+		if !listed[reference.Key()] {
+			missing = append(missing, reference)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	gogithub "github.com/google/go-github/v83/github"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVerifier lists the issues of the listings in turn, repeating the last one, and
+// advances its clock on sleep.
+func fakeVerifier(listings ...[]int) (*labelVerifier, *[]time.Duration) {
+	now := time.Date(2024, 10, 8, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	calls := 0
+
+	return &labelVerifier{
+		listIssues: func(_, _, _ string) ([]*github.Issue, error) {
+			listing := listings[min(calls, len(listings)-1)]
+			calls++
+
+			issues := make([]*github.Issue, 0, len(listing))
+			for _, number := range listing {
+				issues = append(issues, github.NewIssue(&gogithub.Issue{Number: gogithub.Ptr(number)}))
+			}
+			return issues, nil
+		},
+		sleep: func(d time.Duration) {
+			slept = append(slept, d)
+			now = now.Add(d)
+		},
+		now: func() time.Time { return now },
+	}, &slept
+}
+
+var verifiedIssues = []gitlog.IssueReference{
+	{Owner: "camunda", Repo: "camunda", ID: 1},
+	{Owner: "camunda", Repo: "camunda", ID: 2},
+}
+
+func TestWaitUntilListed(t *testing.T) {
+	verifier, slept := fakeVerifier([]int{1}, []int{1}, []int{1, 2})
+
+	err := verifier.waitUntilListed(verifiedIssues, "version:8.6.0", time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{verifyInterval, verifyInterval}, *slept)
+}
+
+func TestWaitUntilListed_Timeout(t *testing.T) {
+	verifier, slept := fakeVerifier([]int{1})
+
+	err := verifier.waitUntilListed(verifiedIssues, "version:8.6.0", time.Minute)
+
+	assert.EqualError(t, err, "GitHub does not list 1 labeled issues with label version:8.6.0 after 1m0s, e.g. https://github.com/camunda/camunda/issues/2")
+	assert.Len(t, *slept, int(time.Minute/verifyInterval)+1)
+}
+
+func TestWaitUntilListed_NothingLabeled(t *testing.T) {
+	verifier, slept := fakeVerifier([]int{})
+
+	err := verifier.waitUntilListed(nil, "version:8.6.0", time.Minute)
+
+	assert.NoError(t, err)
+	assert.Empty(t, *slept)
+}
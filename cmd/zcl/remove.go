@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/progress"
+	"github.com/camunda/zeebe-changelog/pkg/report"
+	"github.com/urfave/cli/v3"
+)
+
+// issueSelection is the way remove-labels selects the issues to remove the label from:
+// by git range, by a list of issues or all issues carrying the label.
+type issueSelection struct {
+	from   string
+	target string
+	issues []string
+	all    bool
+}
+
+func (s issueSelection) byRange() bool {
+	return s.from != "" || s.target != ""
+}
+
+// selectIssues validates that exactly one way of selecting issues is given. A selection
+// which is only given by the profile is ignored if another one is given explicitly, so
+// that e.g. the range of a profile does not conflict with --issues.
+func selectIssues(ctx context.Context, cmd *cli.Command) (issueSelection, error) {
+	selection := issueSelection{
+		from:   cmd.String(fromFlag),
+		target: cmd.String(targetFlag),
+		issues: cmd.StringSlice(issuesFlag),
+		all:    cmd.Bool(allFlag),
+	}
+
+	rangeByProfile := setByProfile(ctx, cmd, fromFlag, targetFlag)
+	issuesByProfile := setByProfile(ctx, cmd, issuesFlag)
+	allByProfile := setByProfile(ctx, cmd, allFlag)
+	if (selection.byRange() && !rangeByProfile) || (len(selection.issues) > 0 && !issuesByProfile) || (selection.all && !allByProfile) {
+		if rangeByProfile {
+			selection.from, selection.target = "", ""
+		}
+		if issuesByProfile {
+			selection.issues = nil
+		}
+		if allByProfile {
+			selection.all = false
+		}
+	}
+
+	modes := 0
+	for _, selected := range []bool{selection.byRange(), len(selection.issues) > 0, selection.all} {
+		if selected {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return selection, usageError(fmt.Errorf("expected exactly one of --%s/--%s, --%s or --%s", fromFlag, targetFlag, issuesFlag, allFlag))
+	}
+	if selection.byRange() && (selection.from == "" || selection.target == "") {
+		return selection, usageError(fmt.Errorf("both --%s and --%s are required to select issues by git range", fromFlag, targetFlag))
+	}
+	return selection, nil
+}
+
+func removeLabels(ctx context.Context, cmd *cli.Command) error {
+	token := cmd.String(gitApiTokenFlag)
+	githubOrg := cmd.String(githubOrgFlag)
+	githubRepo := cmd.String(githubRepoFlag)
+	label := cmd.String(labelFlag)
+	numWorkers := cmd.Int(workersFlag)
+	dryRun := cmd.Bool(dryRunFlag)
+
+	reportFormat, reportFile, err := parseReportFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	if numWorkers <= 0 {
+		return usageError(fmt.Errorf("number of workers must be positive, got: %d", numWorkers))
+	}
+
+	selection, err := selectIssues(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	from, target, issues := selection.from, selection.target, selection.issues
+
+	client := github.NewClient(token)
+
+	var references []gitlog.IssueReference
+	switch {
+	case selection.all:
+		log.Println("Fetching issues with label", label, "in", githubOrg+"/"+githubRepo)
+		labeled, err := client.ListIssues(githubOrg, githubRepo, label)
+		if err != nil {
+			return err
+		}
+		for _, issue := range labeled {
+			references = append(references, gitlog.IssueReference{Owner: githubOrg, Repo: githubRepo, ID: issue.Number()})
+		}
+	case len(issues) > 0:
+		for _, issue := range issues {
+			reference, err := gitlog.ParseIssueReference(issue, githubOrg, githubRepo)
+			if err != nil {
+				return usageError(err)
+			}
+			references = gitlog.AppendUnique(references, reference)
+		}
+	default:
+		references, from, err = collectReferences(cmd, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Println("Fetching current labels of", len(references), "issues")
+	states, err := fetchLabelStates(client, references, label)
+	if err != nil {
+		return err
+	}
+
+	var pending []gitlog.IssueReference
+	counts := map[labelState]int{}
+	for i, reference := range references {
+		counts[states[i]]++
+		if states[i] == labeledState {
+			pending = append(pending, reference)
+		}
+	}
+	issueCount := len(pending)
+
+	if dryRun {
+		log.Println("[dry-run] Would remove label", label, "from", issueCount, "issues in", strings.Join(distinctRepositories(pending), ", "))
+	} else {
+		log.Println("Removing label", label, "from", issueCount, "issues in", strings.Join(distinctRepositories(pending), ", "))
+	}
+	log.Println(counts[unlabeledState], "issues are not labeled,", counts[notFoundState], "issues were not found")
+	for i, reference := range references {
+		state := "remove"
+		if states[i] != labeledState {
+			state = string(states[i])
+		}
+		fmt.Printf("  %s: %s\n", reference.URL(), state)
+	}
+
+	if dryRun || issueCount == 0 {
+		return nil
+	}
+
+	runReport := report.New(label, from, target, report.RemovalOutcomes)
+	for i, reference := range references {
+		switch states[i] {
+		case unlabeledState:
+			runReport.Add(newResult(reference, report.NotLabeled, nil))
+		case notFoundState:
+			runReport.Add(newResult(reference, report.Skipped, errIssueNotFound))
+		}
+	}
+
+	removeErr := newLabelRemover(client).remove(pending, label, selection, cmd.Bool(yesFlag), numWorkers, runReport)
+	if errors.Is(removeErr, errRemovalAborted) {
+		return removeErr
+	}
+	return errors.Join(removeErr, finishReport(runReport, reportFormat, reportFile))
+}
+
+var errRemovalAborted = errors.New("aborted, the label was not removed from any issue")
+
+// labelRemover removes a label from issues. The removal from all issues carrying the label
+// is confirmed on the terminal first. The functions are replaced in tests.
+type labelRemover struct {
+	removeLabel func(owner, repo string, number int, label string) error
+	in          io.Reader
+	out         io.Writer
+}
+
+func newLabelRemover(client *github.Client) *labelRemover {
+	return &labelRemover{
+		removeLabel: func(owner, repo string, number int, label string) error {
+			client.Throttle()
+			return client.RemoveLabel(owner, repo, number, label)
+		},
+		in:  os.Stdin,
+		out: os.Stderr,
+	}
+}
+
+// remove removes the label from the pending issues and adds the outcomes to the report.
+// If all issues carrying the label are selected without --yes, nothing is removed unless
+// the removal is confirmed.
+func (r *labelRemover) remove(pending []gitlog.IssueReference, label string, selection issueSelection, yes bool, numWorkers int, runReport *report.Report) error {
+	if selection.all && !yes {
+		prompt := fmt.Sprintf("Remove label %q from all %d issues in %s?", label, len(pending), strings.Join(distinctRepositories(pending), ", "))
+		if !confirm(r.in, r.out, prompt) {
+			return errRemovalAborted
+		}
+	}
+
+	log.Println("Updating", len(pending), "issues with", numWorkers, "workers")
+	bar := progress.NewProgressBar(len(pending))
+
+	err := processParallel(pending, bar, numWorkers, nil, runReport, func(reference gitlog.IssueReference) (report.Outcome, error) {
+		err := r.removeLabel(reference.Owner, reference.Repo, reference.ID, label)
+		return labelOutcome(report.Removed, err), err
+	})
+	bar.Stop()
+	return err
+}
+
+// confirm asks the question on out and reports whether it was answered with yes on in.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingReader fails the test if the removal asks for confirmation.
+type failingReader struct{ t *testing.T }
+
+func (r failingReader) Read([]byte) (int, error) {
+	r.t.Error("unexpected confirmation prompt")
+	return 0, io.EOF
+}
+
+func TestConfirm(t *testing.T) {
+	tests := map[string]struct {
+		input    io.Reader
+		expected bool
+	}{
+		"Yes":              {input: strings.NewReader("y\n"), expected: true},
+		"Long yes":         {input: strings.NewReader(" YES \n"), expected: true},
+		"Yes without EOL":  {input: strings.NewReader("y"), expected: true},
+		"No":               {input: strings.NewReader("n\n"), expected: false},
+		"Empty input":      {input: strings.NewReader("\n"), expected: false},
+		"EOF":              {input: strings.NewReader(""), expected: false},
+		"Other answer":     {input: strings.NewReader("sure\n"), expected: false},
+		"Only second line": {input: strings.NewReader("\ny\n"), expected: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			assert.Equal(t, tc.expected, confirm(tc.input, &out, "Remove?"))
+			assert.Equal(t, "Remove? [y/N] ", out.String())
+		})
+	}
+}
+
+// fakeRemover records the issues it removes the label from and answers the confirmation
+// prompt with the given input.
+func fakeRemover(in io.Reader) (*labelRemover, *[]string, *bytes.Buffer) {
+	var mutex sync.Mutex
+	var removed []string
+	var out bytes.Buffer
+
+	return &labelRemover{
+		removeLabel: func(owner, repo string, number int, label string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			removed = append(removed, gitlog.IssueReference{Owner: owner, Repo: repo, ID: number}.String())
+			return nil
+		},
+		in:  in,
+		out: &out,
+	}, &removed, &out
+}
+
+var labeledIssues = []gitlog.IssueReference{
+	{Owner: "camunda", Repo: "camunda", ID: 1},
+	{Owner: "camunda", Repo: "camunda", ID: 2},
+}
+
+func TestLabelRemover_AllDeclined(t *testing.T) {
+	remover, removed, out := fakeRemover(strings.NewReader("n\n"))
+	runReport := report.New("version:8.6.0", "", "", report.RemovalOutcomes)
+
+	err := remover.remove(labeledIssues, "version:8.6.0", issueSelection{all: true}, false, 2, runReport)
+
+	assert.True(t, errors.Is(err, errRemovalAborted))
+	assert.Empty(t, *removed)
+	assert.Empty(t, runReport.Results())
+	assert.Equal(t, `Remove label "version:8.6.0" from all 2 issues in camunda/camunda? [y/N] `, out.String())
+}
+
+func TestLabelRemover_AllConfirmed(t *testing.T) {
+	remover, removed, _ := fakeRemover(strings.NewReader("y\n"))
+	runReport := report.New("version:8.6.0", "", "", report.RemovalOutcomes)
+
+	err := remover.remove(labeledIssues, "version:8.6.0", issueSelection{all: true}, false, 2, runReport)
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"camunda/camunda#1", "camunda/camunda#2"}, *removed)
+	assert.Equal(t, 2, runReport.Counts()[report.Removed])
+}
+
+func TestLabelRemover_WithoutConfirmation(t *testing.T) {
+	tests := map[string]struct {
+		selection issueSelection
+		yes       bool
+	}{
+		"All with --yes": {selection: issueSelection{all: true}, yes: true},
+		"Git range":      {selection: issueSelection{from: "8.5.0", target: "8.6.0"}},
+		"Issues":         {selection: issueSelection{issues: []string{"1", "2"}}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			remover, removed, _ := fakeRemover(failingReader{t})
+			runReport := report.New("version:8.6.0", "", "", report.RemovalOutcomes)
+
+			err := remover.remove(labeledIssues, "version:8.6.0", tc.selection, tc.yes, 2, runReport)
+
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"camunda/camunda#1", "camunda/camunda#2"}, *removed)
+		})
+	}
+}
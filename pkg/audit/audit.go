@@ -0,0 +1,142 @@
+// Package audit compares the issues carrying a release label with the issues referenced
+// in the git range of the release.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	openState        = "open"
+	notPlannedReason = "not_planned"
+)
+
+type Finding string
+
+const (
+	// LabeledNotReferenced issues carry the label but are not referenced in the range.
+	LabeledNotReferenced Finding = "labeled-not-referenced"
+	// ReferencedNotLabeled issues are referenced in the range but lack the label.
+	ReferencedNotLabeled Finding = "referenced-not-labeled"
+	// LabeledOpen issues carry the label but are still open.
+	LabeledOpen Finding = "labeled-open"
+	// ClosedNotPlanned issues carry the label but were closed as not planned.
+	ClosedNotPlanned Finding = "closed-not-planned"
+)
+
+// Findings lists all findings in the order they are reported.
+var Findings = []Finding{LabeledNotReferenced, ReferencedNotLabeled, LabeledOpen, ClosedNotPlanned}
+
+// Issue is an issue or pull request, the state is only known for labeled issues.
+type Issue struct {
+	Repository  string `json:"repository"`
+	Number      int    `json:"number"`
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	State       string `json:"state,omitempty"`
+	StateReason string `json:"stateReason,omitempty"`
+}
+
+func (i Issue) key() string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(i.Repository), i.Number)
+}
+
+// Result lists the issues per finding.
+type Result struct {
+	Label    string              `json:"label"`
+	From     string              `json:"from"`
+	Target   string              `json:"target"`
+	Findings map[Finding][]Issue `json:"findings"`
+}
+
+// Audit compares the issues which carry the label with the issues referenced in the range.
+func Audit(label, from, target string, labeled, referenced []Issue) Result {
+	result := Result{Label: label, From: from, Target: target, Findings: make(map[Finding][]Issue, len(Findings))}
+	for _, finding := range Findings {
+		result.Findings[finding] = []Issue{}
+	}
+
+	referencedKeys := make(map[string]bool, len(referenced))
+	for _, issue := range referenced {
+		referencedKeys[issue.key()] = true
+	}
+	labeledKeys := make(map[string]bool, len(labeled))
+	for _, issue := range labeled {
+		labeledKeys[issue.key()] = true
+	}
+
+	for _, issue := range labeled {
+		if !referencedKeys[issue.key()] {
+			result.add(LabeledNotReferenced, issue)
+		}
+		if issue.State == openState {
+			result.add(LabeledOpen, issue)
+		}
+		if issue.StateReason == notPlannedReason {
+			result.add(ClosedNotPlanned, issue)
+		}
+	}
+	for _, issue := range referenced {
+		if !labeledKeys[issue.key()] {
+			result.add(ReferencedNotLabeled, issue)
+		}
+	}
+
+	for _, issues := range result.Findings {
+		sort.SliceStable(issues, func(i, j int) bool {
+			if issues[i].Repository != issues[j].Repository {
+				return issues[i].Repository < issues[j].Repository
+			}
+			return issues[i].Number < issues[j].Number
+		})
+	}
+
+	return result
+}
+
+func (r *Result) add(finding Finding, issue Issue) {
+	r.Findings[finding] = append(r.Findings[finding], issue)
+}
+
+// HasFindings reports whether any issue was found.
+func (r Result) HasFindings() bool {
+	for _, issues := range r.Findings {
+		if len(issues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Table renders the findings as plain text table for the console.
+func (r Result) Table() string {
+	var b bytes.Buffer
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FINDING\tISSUE\tSTATE\tTITLE")
+	for _, finding := range Findings {
+		for _, issue := range r.Findings[finding] {
+			state := issue.State
+			if issue.StateReason != "" {
+				state += " (" + issue.StateReason + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", finding, issue.URL, state, issue.Title)
+		}
+	}
+	w.Flush()
+
+	for _, finding := range Findings {
+		fmt.Fprintf(&b, "%s: %d\n", finding, len(r.Findings[finding]))
+	}
+
+	return b.String()
+}
+
+func (r Result) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func issue(number int, state, stateReason string) Issue {
+	return Issue{
+		Repository:  "camunda/camunda",
+		Number:      number,
+		URL:         fmt.Sprintf("https://github.com/camunda/camunda/issues/%d", number),
+		State:       state,
+		StateReason: stateReason,
+	}
+}
+
+func TestAudit(t *testing.T) {
+	labeled := []Issue{
+		issue(1, "closed", "completed"),
+		issue(2, "open", ""),
+		issue(3, "closed", "not_planned"),
+		issue(4, "closed", "completed"),
+	}
+	referenced := []Issue{
+		{Repository: "Camunda/Camunda", Number: 1},
+		{Repository: "camunda/camunda", Number: 2},
+		{Repository: "camunda/camunda", Number: 3},
+		{Repository: "camunda/connectors", Number: 5},
+	}
+
+	result := Audit("version:8.6.0", "8.5.0", "8.6.0", labeled, referenced)
+
+	assert.True(t, result.HasFindings())
+	assert.Equal(t, []Issue{issue(4, "closed", "completed")}, result.Findings[LabeledNotReferenced])
+	assert.Equal(t, []Issue{{Repository: "camunda/connectors", Number: 5}}, result.Findings[ReferencedNotLabeled])
+	assert.Equal(t, []Issue{issue(2, "open", "")}, result.Findings[LabeledOpen])
+	assert.Equal(t, []Issue{issue(3, "closed", "not_planned")}, result.Findings[ClosedNotPlanned])
+}
+
+func TestAudit_NoFindings(t *testing.T) {
+	result := Audit("version:8.6.0", "8.5.0", "8.6.0",
+		[]Issue{issue(1, "closed", "completed")},
+		[]Issue{{Repository: "camunda/camunda", Number: 1}})
+
+	assert.False(t, result.HasFindings())
+
+	content, err := result.JSON()
+	assert.NoError(t, err)
+
+	var decoded map[string]any
+	assert.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, map[string]any{
+		"labeled-not-referenced": []any{},
+		"referenced-not-labeled": []any{},
+		"labeled-open":           []any{},
+		"closed-not-planned":     []any{},
+	}, decoded["findings"])
+}
+
+func TestTable(t *testing.T) {
+	result := Audit("version:8.6.0", "8.5.0", "8.6.0",
+		[]Issue{{Repository: "camunda/camunda", Number: 2, URL: "https://github.com/camunda/camunda/issues/2", Title: "Flaky test", State: "open"}},
+		[]Issue{{Repository: "camunda/camunda", Number: 1, URL: "https://github.com/camunda/camunda/issues/1"}})
+
+	lines := strings.Split(result.Table(), "\n")
+
+	assert.Equal(t, "FINDING                 ISSUE                                        STATE  TITLE", lines[0])
+	assert.Equal(t, "labeled-not-referenced  https://github.com/camunda/camunda/issues/2  open   Flaky test", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "referenced-not-labeled  https://github.com/camunda/camunda/issues/1"))
+	assert.Equal(t, "labeled-open            https://github.com/camunda/camunda/issues/2  open   Flaky test", lines[3])
+	assert.Equal(t, []string{"labeled-not-referenced: 1", "referenced-not-labeled: 1", "labeled-open: 1", "closed-not-planned: 0", ""}, lines[4:])
+}
@@ -0,0 +1,163 @@
+// Package changelogfile updates changelog files like CHANGELOG.md in place. Every
+// generated changelog is enclosed in begin and end comments naming its label, so that it
+// can be replaced when the changelog is generated again, while the rest of the file is
+// left untouched.
+package changelogfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMarker is the line below which new changelogs are inserted.
+const DefaultMarker = "<!-- zcl:changelog -->"
+
+var ErrMarkerNotFound = errors.New("marker not found")
+
+func beginComment(label string) string {
+	return fmt.Sprintf("<!-- zcl:begin %s -->", label)
+}
+
+func endComment(label string) string {
+	return fmt.Sprintf("<!-- zcl:end %s -->", label)
+}
+
+// Insert returns the content with the changelog of the label. An existing block of the
+// label is replaced, otherwise the block is inserted below the marker line, followed by
+// an empty line. All other bytes of the content are preserved.
+func Insert(content []byte, marker, label string, changelog []byte) ([]byte, error) {
+	var block bytes.Buffer
+	block.WriteString(beginComment(label) + "\n")
+	block.Write(changelog)
+	if len(changelog) > 0 && changelog[len(changelog)-1] != '\n' {
+		block.WriteByte('\n')
+	}
+	block.WriteString(endComment(label))
+
+	start, end, found, err := findBlock(content, label)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return concat(content[:start], block.Bytes(), content[end:]), nil
+	}
+
+	markerEnd, found := findLine(content, marker)
+	if !found {
+		return nil, fmt.Errorf("%w: no line %q to insert the changelog below", ErrMarkerNotFound, marker)
+	}
+
+	var prefix, suffix []byte
+	if markerEnd < len(content) {
+		prefix, suffix = content[:markerEnd+1], content[markerEnd+1:]
+	} else {
+		// the marker is the last line and has no line break
+		prefix = concat(content, []byte("\n"))
+	}
+
+	block.WriteString("\n")
+	if len(suffix) > 0 {
+		// separate the block from the previous changelogs
+		block.WriteString("\n")
+	}
+
+	return concat(prefix, block.Bytes(), suffix), nil
+}
+
+// findBlock returns the start of the begin comment and the end of the end comment of
+// the label's block.
+func findBlock(content []byte, label string) (int, int, bool, error) {
+	begin := []byte(beginComment(label))
+	end := []byte(endComment(label))
+
+	start := bytes.Index(content, begin)
+	if start < 0 {
+		return 0, 0, false, nil
+	}
+	if bytes.Contains(content[start+len(begin):], begin) {
+		return 0, 0, false, fmt.Errorf("multiple changelogs of %s found", label)
+	}
+
+	length := bytes.Index(content[start:], end)
+	if length < 0 {
+		return 0, 0, false, fmt.Errorf("changelog of %s has no end comment %q", label, end)
+	}
+
+	return start, start + length + len(end), true, nil
+}
+
+// findLine returns the end of the first line which equals the text, ignoring trailing
+// whitespace like carriage returns.
+func findLine(content []byte, text string) (int, bool) {
+	for offset := 0; offset < len(content); {
+		lineEnd := bytes.IndexByte(content[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(content) - offset
+		}
+
+		if string(bytes.TrimRight(content[offset:offset+lineEnd], " \t\r")) == text {
+			return offset + lineEnd, true
+		}
+		offset += lineEnd + 1
+	}
+	return 0, false
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// InsertFile inserts the changelog of the label into the file, see Insert. A file which
+// does not exist is created with the marker. The file is replaced atomically, so that it
+// is never left half written.
+func InsertFile(path, marker, label string, changelog []byte) error {
+	content, err := os.ReadFile(path)
+	mode := fs.FileMode(0o644)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		content = []byte(marker + "\n")
+	case err != nil:
+		return fmt.Errorf("unable to read changelog file: %w", err)
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	updated, err := Insert(content, marker, label, changelog)
+	if err != nil {
+		return fmt.Errorf("unable to update changelog file %s: %w", path, err)
+	}
+
+	return WriteFile(path, updated, mode)
+}
+
+// WriteFile replaces the file atomically by writing the content to a temporary file in
+// the same directory and renaming it.
+func WriteFile(path string, content []byte, mode fs.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write changelog file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to write changelog file %s: %w", path, err)
+	}
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to write changelog file %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to write changelog file %s: %w", path, err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("unable to write changelog file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package changelogfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const existing = `# Changelog
+
+Intro text.
+
+<!-- zcl:changelog -->
+<!-- zcl:begin version:8.5.0 -->
+# version:8.5.0
+* Old ([#1](u1))
+<!-- zcl:end version:8.5.0 -->
+
+Footer  
+`
+
+func TestInsert_NewBlock(t *testing.T) {
+	updated, err := Insert([]byte(existing), DefaultMarker, "version:8.6.0", []byte("# version:8.6.0\n* New ([#2](u2))\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# Changelog
+
+Intro text.
+
+<!-- zcl:changelog -->
+<!-- zcl:begin version:8.6.0 -->
+# version:8.6.0
+* New ([#2](u2))
+<!-- zcl:end version:8.6.0 -->
+
+<!-- zcl:begin version:8.5.0 -->
+# version:8.5.0
+* Old ([#1](u1))
+<!-- zcl:end version:8.5.0 -->
+
+Footer  
+`, string(updated))
+}
+
+func TestInsert_ReplaceBlock(t *testing.T) {
+	updated, err := Insert([]byte(existing), DefaultMarker, "version:8.5.0", []byte("# version:8.5.0\n* Updated ([#3](u3))"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# Changelog
+
+Intro text.
+
+<!-- zcl:changelog -->
+<!-- zcl:begin version:8.5.0 -->
+# version:8.5.0
+* Updated ([#3](u3))
+<!-- zcl:end version:8.5.0 -->
+
+Footer  
+`, string(updated))
+}
+
+func TestInsert_Idempotent(t *testing.T) {
+	changelog := []byte("# version:8.6.0\n* New ([#2](u2))\n")
+
+	once, err := Insert([]byte(existing), DefaultMarker, "version:8.6.0", changelog)
+	assert.NoError(t, err)
+	twice, err := Insert(once, DefaultMarker, "version:8.6.0", changelog)
+	assert.NoError(t, err)
+
+	assert.Equal(t, string(once), string(twice))
+}
+
+func TestInsert_MarkerOnLastLine(t *testing.T) {
+	updated, err := Insert([]byte("# Changelog\r\n<!-- releases -->"), "<!-- releases -->", "v1", []byte("* Entry\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# Changelog\r\n<!-- releases -->\n<!-- zcl:begin v1 -->\n* Entry\n<!-- zcl:end v1 -->\n", string(updated))
+}
+
+func TestInsert_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		err     string
+	}{
+		"Missing marker":    {content: "# Changelog\n", err: "marker not found"},
+		"Marker not a line": {content: "see <!-- zcl:changelog --> below\n", err: "marker not found"},
+		"Missing end": {
+			content: "<!-- zcl:changelog -->\n<!-- zcl:begin v1 -->\n* Entry\n",
+			err:     `changelog of v1 has no end comment "<!-- zcl:end v1 -->"`,
+		},
+		"Duplicate block": {
+			content: "<!-- zcl:begin v1 -->\n<!-- zcl:end v1 -->\n<!-- zcl:begin v1 -->\n<!-- zcl:end v1 -->\n",
+			err:     "multiple changelogs of v1 found",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Insert([]byte(tc.content), DefaultMarker, "v1", []byte("* Entry\n"))
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestInsertFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	assert.NoError(t, os.WriteFile(path, []byte(existing), 0o600))
+
+	err := InsertFile(path, DefaultMarker, "version:8.5.0", []byte("# version:8.5.0\n* Updated ([#3](u3))\n"))
+
+	assert.NoError(t, err)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "* Updated ([#3](u3))\n<!-- zcl:end version:8.5.0 -->\n\nFooter  \n")
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file should be removed")
+}
+
+func TestInsertFile_Create(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	err := InsertFile(path, DefaultMarker, "v1", []byte("* Entry\n"))
+
+	assert.NoError(t, err)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "<!-- zcl:changelog -->\n<!-- zcl:begin v1 -->\n* Entry\n<!-- zcl:end v1 -->\n", string(content))
+}
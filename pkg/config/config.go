@@ -0,0 +1,133 @@
+// Package config reads the project configuration file .zcl.yaml, which holds named
+// profiles of flag values, e.g. one per product or stable branch.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file in the root of the git working tree.
+const FileName = ".zcl.yaml"
+
+// pathKeys are the flags whose relative paths are resolved against the directory of the
+// configuration file, so that profiles work from every working directory.
+var pathKeys = []string{"categories", "template", "output", "journal", "report-file", "state"}
+
+// Config is the content of the configuration file.
+type Config struct {
+	// Default is the profile used if no profile is selected.
+	Default string `yaml:"default"`
+	// Profiles map flag names to their values, lists are used for flags which can be
+	// given multiple times.
+	Profiles map[string]map[string]any `yaml:"profiles"`
+
+	dir string
+}
+
+// Discover returns the path of the configuration file in the root of the git working
+// tree which contains dir, or false if there is none.
+func Discover(dir string) (string, bool) {
+	toplevel, ok := gitlog.Toplevel(dir)
+	if !ok {
+		return "", false
+	}
+
+	path := filepath.Join(toplevel, FileName)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// Load reads the configuration file.
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse configuration %s: %w", path, err)
+	}
+	if config.Default != "" && config.Profiles[config.Default] == nil {
+		return nil, fmt.Errorf("invalid configuration %s: default profile %q does not exist", path, config.Default)
+	}
+
+	config.dir = filepath.Dir(path)
+	return &config, nil
+}
+
+// Profile returns the flag values of the named profile, or of the default profile if the
+// name is empty. Without name and default profile no values are returned.
+func (c *Config) Profile(name string) (map[string][]string, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return map[string][]string{}, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q, expected one of: %s", name, strings.Join(c.profileNames(), ", "))
+	}
+
+	values := make(map[string][]string, len(profile))
+	for key, value := range profile {
+		flagValues, err := toValues(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s in profile %q: %w", key, name, err)
+		}
+		if slices.Contains(pathKeys, key) {
+			for i, path := range flagValues {
+				if path != "" && !filepath.IsAbs(path) {
+					flagValues[i] = filepath.Join(c.dir, path)
+				}
+			}
+		}
+		values[key] = flagValues
+	}
+
+	return values, nil
+}
+
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func toValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, errors.New("value is missing")
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, element := range v {
+			if _, ok := element.([]any); ok {
+				return nil, errors.New("nested lists are not supported")
+			}
+			if _, ok := element.(map[string]any); ok {
+				return nil, errors.New("maps are not supported")
+			}
+			values = append(values, fmt.Sprint(element))
+		}
+		return values, nil
+	case map[string]any:
+		return nil, errors.New("maps are not supported")
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `default: camunda
+profiles:
+  camunda:
+    org: camunda
+    repo: camunda
+    label: version:{target}
+    workers: 20
+    resolve-prs: true
+    allowed-repo: [camunda/camunda, camunda/connectors]
+    categories: .zcl/categories.yaml
+    output: /tmp/CHANGELOG.md
+  stable-8.5:
+    org: camunda
+    repo: zeebe
+    include-path: ["zeebe/**"]
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestConfig_Profile(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	config, err := Load(path)
+	assert.NoError(t, err)
+
+	values, err := config.Profile("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"org":          {"camunda"},
+		"repo":         {"camunda"},
+		"label":        {"version:{target}"},
+		"workers":      {"20"},
+		"resolve-prs":  {"true"},
+		"allowed-repo": {"camunda/camunda", "camunda/connectors"},
+		"categories":   {filepath.Join(filepath.Dir(path), ".zcl/categories.yaml")},
+		"output":       {"/tmp/CHANGELOG.md"},
+	}, values)
+
+	values, err = config.Profile("stable-8.5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"org": {"camunda"}, "repo": {"zeebe"}, "include-path": {"zeebe/**"}}, values)
+}
+
+func TestConfig_WithoutDefault(t *testing.T) {
+	config, err := Load(writeConfig(t, "profiles:\n  camunda:\n    org: camunda\n"))
+	assert.NoError(t, err)
+
+	values, err := config.Profile("")
+
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestConfig_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		profile string
+		err     string
+	}{
+		"Unknown profile": {content: testConfig, profile: "operate", err: `unknown profile "operate", expected one of: camunda, stable-8.5`},
+		"Unknown default": {content: "default: operate\nprofiles: {}", err: `default profile "operate" does not exist`},
+		"Invalid YAML":    {content: "profiles: [", err: "unable to parse configuration"},
+		"Map value":       {content: "profiles:\n  p:\n    org: {name: camunda}", profile: "p", err: "invalid value of org in profile \"p\": maps are not supported"},
+		"Missing value":   {content: "profiles:\n  p:\n    org:", profile: "p", err: "value is missing"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := Load(writeConfig(t, tc.content))
+			if err == nil {
+				_, err = config.Profile(tc.profile)
+			}
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git is not available: %v %s", err, out)
+	}
+	subdir := filepath.Join(dir, "zeebe", "engine")
+	assert.NoError(t, os.MkdirAll(subdir, 0o755))
+
+	_, found := Discover(subdir)
+	assert.False(t, found)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, FileName), []byte(testConfig), 0o644))
+	path, found := Discover(subdir)
+
+	assert.True(t, found)
+	resolved, _ := filepath.EvalSymlinks(filepath.Join(dir, FileName))
+	actual, _ := filepath.EvalSymlinks(path)
+	assert.Equal(t, resolved, actual)
+}
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy decides whether an issue is added to all matching chapters or sections, or only
+// to the first one.
+type Policy string
+
+const (
+	AllMatch   Policy = "all-match"
+	FirstMatch Policy = "first-match"
+)
+
+// Categories define the chapters of the changelog and the sections within chapters, in the
+// order they are rendered.
+type Categories struct {
+	Chapters []ChapterConfig `yaml:"chapters"`
+	// Policy assigns issues to chapters, the default is AllMatch.
+	Policy Policy `yaml:"policy"`
+}
+
+// ChapterConfig is a top-level chapter of the changelog. Issues are added to the chapters
+// which match one of their labels or their rule, a chapter without labels and rule matches
+// all issues.
+type ChapterConfig struct {
+	Title  string   `yaml:"title"`
+	Labels []string `yaml:"labels"`
+	// Rule is a rule expression, see ParseRule, which selects the issues instead of labels.
+	Rule string `yaml:"rule"`
+	// PullRequests chapters list pull requests instead of issues.
+	PullRequests bool            `yaml:"pullRequests"`
+	Sections     []SectionConfig `yaml:"sections"`
+	// Policy assigns issues to the sections of the chapter, the default is AllMatch.
+	Policy Policy `yaml:"policy"`
+	// Fallback is the title of the section for issues which match no section of the chapter.
+	Fallback string `yaml:"fallback"`
+}
+
+// SectionConfig is a sub-section of a chapter, which lists the issues of the chapter that
+// carry one of the labels or match the rule.
+type SectionConfig struct {
+	Title  string   `yaml:"title"`
+	Labels []string `yaml:"labels"`
+	Rule   string   `yaml:"rule"`
+}
+
+// defaultSections are the components the enhancements and bug fixes are grouped by.
+var defaultSections = []SectionConfig{
+	{Title: brokerSection, Labels: []string{brokerLabel}},
+	{Title: gatewaySection, Labels: []string{gatewayLabel}},
+	{Title: javaClientSection, Labels: []string{javaClientLabel}},
+	{Title: goClientSection, Labels: []string{goClientLabel}},
+	{Title: zbctlSection, Labels: []string{zbctlLabel}},
+}
+
+// DefaultCategories returns the classic layout of the Zeebe changelog based on the kind/
+// and scope/ labels.
+func DefaultCategories() Categories {
+	return Categories{Chapters: []ChapterConfig{
+		{Title: "Enhancements", Labels: []string{featureLabel}, Sections: defaultSections, Fallback: miscSection},
+		{Title: "Bug Fixes", Labels: []string{bugLabel, supportLabel}, Sections: defaultSections, Fallback: miscSection},
+		{Title: "Maintenance", Labels: []string{toilLabel}},
+		{Title: "Task", Labels: []string{taskLabel}},
+		{Title: "Documentation", Labels: []string{docsLabel}},
+		{Title: "Merged Pull Requests", PullRequests: true},
+	}}
+}
+
+// LoadCategories reads the changelog categories from a YAML file.
+func LoadCategories(path string) (Categories, error) {
+	var categories Categories
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return categories, fmt.Errorf("unable to read changelog categories: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &categories); err != nil {
+		return categories, fmt.Errorf("unable to parse changelog categories %s: %w", path, err)
+	}
+
+	if err := categories.validate(); err != nil {
+		return categories, fmt.Errorf("invalid changelog categories %s: %w", path, err)
+	}
+
+	return categories, nil
+}
+
+func (c Categories) validate() error {
+	if len(c.Chapters) == 0 {
+		return errors.New("at least one chapter is required")
+	}
+	if err := c.Policy.validate(); err != nil {
+		return err
+	}
+
+	for i, chapter := range c.Chapters {
+		if chapter.Title == "" {
+			return fmt.Errorf("chapter %d has no title", i+1)
+		}
+		if _, err := newMatcher(chapter.Labels, chapter.Rule); err != nil {
+			return fmt.Errorf("chapter %q: %w", chapter.Title, err)
+		}
+		if err := chapter.Policy.validate(); err != nil {
+			return fmt.Errorf("chapter %q: %w", chapter.Title, err)
+		}
+		for j, section := range chapter.Sections {
+			if section.Title == "" {
+				return fmt.Errorf("section %d of chapter %q has no title", j+1, chapter.Title)
+			}
+			if len(section.Labels) == 0 && section.Rule == "" {
+				return fmt.Errorf("section %q of chapter %q has no labels or rule", section.Title, chapter.Title)
+			}
+			if _, err := newMatcher(section.Labels, section.Rule); err != nil {
+				return fmt.Errorf("section %q of chapter %q: %w", section.Title, chapter.Title, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p Policy) validate() error {
+	switch p {
+	case "", AllMatch, FirstMatch:
+		return nil
+	default:
+		return fmt.Errorf("unknown policy %q, expected %s or %s", p, AllMatch, FirstMatch)
+	}
+}
+
+// matcher selects the issues of a chapter or section either by labels or by rule.
+type matcher struct {
+	labels []string
+	rule   *Rule
+}
+
+func newMatcher(labels []string, rule string) (matcher, error) {
+	if rule == "" {
+		return matcher{labels: labels}, nil
+	}
+	if len(labels) > 0 {
+		return matcher{}, errors.New("labels and rule are mutually exclusive")
+	}
+
+	parsed, err := ParseRule(rule)
+	if err != nil {
+		return matcher{}, err
+	}
+	return matcher{rule: parsed}, nil
+}
+
+// matches reports whether the issue carries one of the labels or fulfills the rule, no
+// labels match every issue.
+func (m matcher) matches(issue *Issue) bool {
+	if m.rule != nil {
+		return m.rule.Matches(issue)
+	}
+	if len(m.labels) == 0 {
+		return true
+	}
+	for _, label := range m.labels {
+		if issue.hasLabel(label) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,45 +6,63 @@ import (
 )
 
 type Changelog struct {
-	title        string
-	enhancements *Section
-	fixes        *Section
-	docs         []*Issue
-	toil         []*Issue
-	task         []*Issue
-	pullRequests []*Issue
+	title    string
+	policy   Policy
+	chapters []*chapter
 }
 
+type chapter struct {
+	config  ChapterConfig
+	matcher matcher
+	section *Section
+	issues  []*Issue
+}
+
+// NewChangelog creates a changelog with the default categories.
 func NewChangelog(title string) *Changelog {
-	return &Changelog{
-		title:        title,
-		enhancements: NewSection(),
-		fixes:        NewSection(),
-		docs:         []*Issue{},
-		toil:         []*Issue{},
-		task:         []*Issue{},
-		pullRequests: []*Issue{},
-	}
+	// the default categories select issues by labels only and cannot fail
+	changelog, _ := NewChangelogWithCategories(title, DefaultCategories())
+	return changelog
 }
 
-func (c *Changelog) AddIssue(issue *Issue) *Changelog {
-	if issue.IsPullRequest() {
-		c.pullRequests = append(c.pullRequests, issue)
-	} else {
-		if issue.HasEnhancementLabel() {
-			c.enhancements.AddIssue(issue)
+// NewChangelogWithCategories creates a changelog with the chapters and sections of the
+// given categories. It fails if a rule of the categories is invalid.
+func NewChangelogWithCategories(title string, categories Categories) (*Changelog, error) {
+	changelog := &Changelog{title: title, policy: categories.Policy}
+	for _, config := range categories.Chapters {
+		m, err := newMatcher(config.Labels, config.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("chapter %q: %w", config.Title, err)
 		}
-		if issue.HasBugLabel() {
-			c.fixes.AddIssue(issue)
+
+		c := &chapter{config: config, matcher: m, issues: []*Issue{}}
+		if len(config.Sections) > 0 {
+			c.section, err = newSection(config.Sections, config.Policy, config.Fallback)
+			if err != nil {
+				return nil, fmt.Errorf("chapter %q: %w", config.Title, err)
+			}
 		}
-		if issue.HasDocsLabel() {
-			c.docs = append(c.docs, issue)
+		changelog.chapters = append(changelog.chapters, c)
+	}
+	return changelog, nil
+}
+
+// AddIssue adds the issue to every matching chapter, or only to the first one with the
+// FirstMatch policy. Pull requests are only added to pull request chapters and issues
+// only to the other chapters.
+func (c *Changelog) AddIssue(issue *Issue) *Changelog {
+	for _, chapter := range c.chapters {
+		if chapter.config.PullRequests != issue.IsPullRequest() || !chapter.matcher.matches(issue) {
+			continue
 		}
-		if issue.HasToilLabel() {
-			c.toil = append(c.toil, issue)
+
+		if chapter.section != nil {
+			chapter.section.AddIssue(issue)
+		} else {
+			chapter.issues = append(chapter.issues, issue)
 		}
-		if issue.HasTaskLabel() {
-			c.task = append(c.task, issue)
+		if c.policy == FirstMatch {
+			break
 		}
 	}
 	return c
@@ -52,31 +70,7 @@ func (c *Changelog) AddIssue(issue *Issue) *Changelog {
 
 func (c *Changelog) String() string {
 	var b bytes.Buffer
-
-	b.WriteString(fmt.Sprintf("# %s\n", c.title))
-
-	chapterToString(&b, "Enhancements", c.enhancements)
-	chapterToString(&b, "Bug Fixes", c.fixes)
-
-	issueListToString(&b, "Maintenance", c.toil)
-	issueListToString(&b, "Task", c.task)
-	issueListToString(&b, "Documentation", c.docs)
-	issueListToString(&b, "Merged Pull Requests", c.pullRequests)
-
+	// the default template is covered by tests and rendering into a buffer cannot fail
+	_ = c.Render(&b, DefaultTemplate())
 	return b.String()
 }
-
-func issueListToString(b *bytes.Buffer, title string, issues []*Issue) {
-	if len(issues) > 0 {
-		b.WriteString(fmt.Sprintf("## %s\n", title))
-		for _, issue := range issues {
-			b.WriteString(fmt.Sprintf("* %s\n", issue.String()))
-		}
-	}
-}
-
-func chapterToString(b *bytes.Buffer, title string, section *Section) {
-	if !section.IsEmpty() {
-		b.WriteString(fmt.Sprintf("## %s\n%s", title, section.String()))
-	}
-}
@@ -1,8 +1,11 @@
 package github
 
 import (
-	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestChangelog_String(t *testing.T) {
@@ -18,3 +21,152 @@ func TestChangelog_String(t *testing.T) {
 		})
 	}
 }
+
+func TestChangelog_DefaultCategories(t *testing.T) {
+	changelog := NewChangelog("Test").
+		AddIssue(createIssue("Feature", 1, "u1", false, featureLabel, brokerLabel)).
+		AddIssue(createIssue("Bug", 2, "u2", false, bugLabel)).
+		AddIssue(createIssue("Support", 3, "u3", false, supportLabel, zbctlLabel)).
+		AddIssue(createIssue("Toil", 4, "u4", false, toilLabel)).
+		AddIssue(createIssue("Task", 5, "u5", false, taskLabel)).
+		AddIssue(createIssue("Docs", 6, "u6", false, docsLabel)).
+		AddIssue(createIssue("PR", 7, "u7", true, featureLabel)).
+		AddIssue(createIssue("Unknown", 8, "u8", false, "component/operate"))
+
+	expected := "# Test\n" +
+		"## Enhancements\n### Broker\n* Feature ([#1](u1))\n" +
+		"## Bug Fixes\n### zbctl\n* Support ([#3](u3))\n### Misc\n* Bug ([#2](u2))\n" +
+		"## Maintenance\n* Toil ([#4](u4))\n" +
+		"## Task\n* Task ([#5](u5))\n" +
+		"## Documentation\n* Docs ([#6](u6))\n" +
+		"## Merged Pull Requests\n* PR ([#7](u7))\n"
+
+	assert.Equal(t, expected, changelog.String())
+}
+
+func TestChangelog_CustomCategories(t *testing.T) {
+	categories := Categories{Chapters: []ChapterConfig{
+		{Title: "Highlights", Labels: []string{"kind/epic"}},
+		{
+			Title:    "Bug Fixes",
+			Labels:   []string{"kind/bug"},
+			Sections: []SectionConfig{{Title: "Zeebe", Labels: []string{"component/zeebe"}}, {Title: "Operate", Labels: []string{"component/operate"}}},
+		},
+		{Title: "Other Changes", PullRequests: true, Labels: []string{"dependencies"}},
+	}}
+
+	changelog, err := NewChangelogWithCategories("8.6.0", categories)
+	assert.NoError(t, err)
+	changelog.
+		AddIssue(createIssue("Epic", 1, "u1", false, "kind/epic")).
+		AddIssue(createIssue("Engine bug", 2, "u2", false, "kind/bug", "component/zeebe")).
+		AddIssue(createIssue("UI bug", 3, "u3", false, "kind/bug", "component/operate")).
+		AddIssue(createIssue("Other bug", 4, "u4", false, "kind/bug")).
+		AddIssue(createIssue("Bump", 5, "u5", true, "dependencies")).
+		AddIssue(createIssue("Feature PR", 6, "u6", true, "kind/epic"))
+
+	expected := "# 8.6.0\n" +
+		"## Highlights\n* Epic ([#1](u1))\n" +
+		"## Bug Fixes\n* Other bug ([#4](u4))\n### Zeebe\n* Engine bug ([#2](u2))\n### Operate\n* UI bug ([#3](u3))\n" +
+		"## Other Changes\n* Bump ([#5](u5))\n"
+
+	assert.Equal(t, expected, changelog.String())
+}
+
+func TestChangelog_RulesAndPolicies(t *testing.T) {
+	categories := Categories{
+		Policy: FirstMatch,
+		Chapters: []ChapterConfig{
+			{Title: "Security", Rule: `title matches "^\[Security\]"`},
+			{
+				Title:  "Bug Fixes",
+				Rule:   "kind/bug AND NOT support",
+				Policy: FirstMatch,
+				Sections: []SectionConfig{
+					{Title: "Engine", Rule: "component/zeebe OR component/gateway"},
+					{Title: "Zeebe", Labels: []string{"component/zeebe"}},
+				},
+			},
+			{Title: "Other"},
+		},
+	}
+
+	changelog, err := NewChangelogWithCategories("8.6.0", categories)
+	assert.NoError(t, err)
+	changelog.
+		AddIssue(createIssue("[Security] Bug", 1, "u1", false, "kind/bug", "component/zeebe")).
+		AddIssue(createIssue("Engine bug", 2, "u2", false, "kind/bug", "component/zeebe")).
+		AddIssue(createIssue("Support bug", 3, "u3", false, "kind/bug", "support"))
+
+	expected := "# 8.6.0\n" +
+		"## Security\n* [Security] Bug ([#1](u1))\n" +
+		"## Bug Fixes\n### Engine\n* Engine bug ([#2](u2))\n" +
+		"## Other\n* Support bug ([#3](u3))\n"
+
+	assert.Equal(t, expected, changelog.String())
+}
+
+func TestNewChangelogWithCategories_InvalidRule(t *testing.T) {
+	categories := Categories{Chapters: []ChapterConfig{
+		{Title: "Bugs", Sections: []SectionConfig{{Title: "Zeebe", Rule: "component/zeebe AND"}}},
+	}}
+
+	_, err := NewChangelogWithCategories("8.6.0", categories)
+
+	assert.ErrorContains(t, err, `chapter "Bugs": section "Zeebe": invalid rule`)
+}
+
+func TestLoadCategories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "categories.yaml")
+	content := `chapters:
+  - title: Enhancements
+    labels: [kind/feature, kind/epic]
+    sections:
+      - title: Zeebe
+        labels: [component/zeebe]
+    fallback: Misc
+  - title: Security
+    rule: title matches "^\[Security\]"
+    policy: first-match
+  - title: Merged Pull Requests
+    pullRequests: true
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	categories, err := LoadCategories(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Categories{Chapters: []ChapterConfig{
+		{Title: "Enhancements", Labels: []string{"kind/feature", "kind/epic"}, Sections: []SectionConfig{{Title: "Zeebe", Labels: []string{"component/zeebe"}}}, Fallback: "Misc"},
+		{Title: "Security", Rule: `title matches "^\[Security\]"`, Policy: FirstMatch},
+		{Title: "Merged Pull Requests", PullRequests: true},
+	}}, categories)
+}
+
+func TestLoadCategories_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		err     string
+	}{
+		"No chapters":   {content: "chapters: []", err: "at least one chapter is required"},
+		"Missing title": {content: "chapters:\n  - labels: [kind/bug]", err: "chapter 1 has no title"},
+		"Section label": {content: "chapters:\n  - title: Bugs\n    sections:\n      - title: Zeebe", err: `section "Zeebe" of chapter "Bugs" has no labels or rule`},
+		"Invalid YAML":  {content: "chapters: {", err: "unable to parse changelog categories"},
+		"Invalid rule":  {content: "chapters:\n  - title: Bugs\n    rule: kind/bug OR", err: `chapter "Bugs": invalid rule`},
+		"Labels and rule": {
+			content: "chapters:\n  - title: Bugs\n    labels: [kind/bug]\n    rule: kind/bug",
+			err:     "labels and rule are mutually exclusive",
+		},
+		"Unknown policy": {content: "policy: any\nchapters:\n  - title: Bugs", err: `unknown policy "any"`},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "categories.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0o644))
+
+			_, err := LoadCategories(path)
+
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -2,10 +2,13 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/go-github/v83/github"
 	"golang.org/x/oauth2"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -15,9 +18,17 @@ const (
 )
 
 type Client struct {
-	ctx    context.Context
-	client *github.Client
-	sleep  func(time.Duration)
+	ctx        context.Context
+	client     *github.Client
+	sleep      func(time.Duration)
+	now        func() time.Time
+	maxRetries int
+
+	rateMutex sync.Mutex
+	rate      github.Rate
+	// nextRequest is the time reserved by the last throttled request, so that concurrent
+	// workers spread their requests instead of waiting the same time in parallel.
+	nextRequest time.Time
 }
 
 func NewClient(token string) *Client {
@@ -30,26 +41,28 @@ func NewClient(token string) *Client {
 	client := github.NewClient(tc)
 
 	return &Client{
-		ctx:    ctx,
-		client: client,
-		sleep:  time.Sleep,
+		ctx:        ctx,
+		client:     client,
+		sleep:      time.Sleep,
+		now:        time.Now,
+		maxRetries: defaultMaxRetries,
 	}
 }
 
-func (ghc *Client) EnsureLabelExists(githubOrg, githubRepo, label string, dryRun bool) {
+func (ghc *Client) EnsureLabelExists(githubOrg, githubRepo, label string, dryRun bool) error {
 	exists, err := ghc.LabelExists(githubOrg, githubRepo, label)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
 
 	log.Printf("Does label %q exist in %s/%s: %t\n", label, githubOrg, githubRepo, exists)
 
 	if dryRun || exists {
-		return
+		return nil
 	}
 
 	log.Printf("Label %q was not found in %s/%s. Creating it...\n", label, githubOrg, githubRepo)
-	_, _, _ = ghc.client.Issues.CreateLabel(ghc.ctx, githubOrg, githubRepo, &github.Label{
+	_, _, createErr := ghc.client.Issues.CreateLabel(ghc.ctx, githubOrg, githubRepo, &github.Label{
 		Name:  github.Ptr(label),
 		Color: github.Ptr(defaultLabelColor),
 	})
@@ -58,52 +71,130 @@ func (ghc *Client) EnsureLabelExists(githubOrg, githubRepo, label string, dryRun
 
 	exists, err = ghc.LabelExists(githubOrg, githubRepo, label)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
 	if !exists {
-		log.Fatalf("Unable to verify creation of label %q in %s/%s\n", label, githubOrg, githubRepo)
+		if createErr != nil {
+			return fmt.Errorf("unable to create label %q in %s/%s: %w", label, githubOrg, githubRepo, classifyError(createErr))
+		}
+		return fmt.Errorf("unable to verify creation of label %q in %s/%s", label, githubOrg, githubRepo)
 	}
+
+	return nil
 }
 
 func (ghc *Client) LabelExists(githubOrg, githubRepo, label string) (bool, error) {
-	_, _, err := ghc.client.Issues.GetLabel(ghc.ctx, githubOrg, githubRepo, label)
+	err := ghc.withRetry(func() (*github.Response, error) {
+		_, response, err := ghc.client.Issues.GetLabel(ghc.ctx, githubOrg, githubRepo, label)
+		return response, err
+	})
 	if err == nil {
 		return true, nil
 	}
 
-	if errResp, ok := err.(*github.ErrorResponse); ok && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+	if statusCode(err) == http.StatusNotFound {
 		return false, nil
 	}
 
-	return false, err
+	return false, fmt.Errorf("unable to look up label %q in %s/%s: %w", label, githubOrg, githubRepo, classifyError(err))
+}
+
+// AddLabel adds the label to the issue. Issues which do not exist or cannot be labeled
+// are skipped with a warning, all other failures are returned.
+func (ghc *Client) AddLabel(githubOrg string, githubRepo string, issueId int, label string) error {
+	err := ghc.LabelIssue(githubOrg, githubRepo, issueId, label)
+	if errors.Is(err, ErrNotLabelable) {
+		log.Printf("Warning: Issue #%d could not be labeled in %s/%s, skipping label addition: %v\n", issueId, githubOrg, githubRepo, err)
+		return nil
+	}
+	return err
 }
 
-func (ghc *Client) AddLabel(githubOrg string, githubRepo string, issueId int, label string) {
-	_, _, err := ghc.client.Issues.AddLabelsToIssue(ghc.ctx, githubOrg, githubRepo, issueId, []string{label})
+// LabelIssue adds the label to the issue. Failures because the issue does not exist or
+// cannot be labeled are returned as ErrNotLabelable, so that callers can skip them.
+func (ghc *Client) LabelIssue(githubOrg string, githubRepo string, issueId int, label string) error {
+	err := ghc.withRetry(func() (*github.Response, error) {
+		_, response, err := ghc.client.Issues.AddLabelsToIssue(ghc.ctx, githubOrg, githubRepo, issueId, []string{label})
+		return response, err
+	})
 	if err != nil {
-		if errResp, ok := err.(*github.ErrorResponse); ok && errResp.Response != nil {
-			if errResp.Response.StatusCode == http.StatusNotFound ||
-				errResp.Response.StatusCode == http.StatusUnprocessableEntity {
-				log.Printf("Warning: Issue #%d could not be labeled in %s/%s, skipping label addition: %v\n", issueId, githubOrg, githubRepo, err)
-				return
-			}
+		if code := statusCode(err); code == http.StatusNotFound || code == http.StatusUnprocessableEntity {
+			return fmt.Errorf("%w: %w", ErrNotLabelable, err)
 		}
-		log.Fatalln(err)
+		return fmt.Errorf("unable to label issue #%d in %s/%s: %w", issueId, githubOrg, githubRepo, classifyError(err))
 	}
+	return nil
+}
+
+// RemoveLabel removes the label from the issue. Failures because the issue does not
+// exist or does not carry the label are returned as ErrNotLabelable.
+func (ghc *Client) RemoveLabel(githubOrg string, githubRepo string, issueId int, label string) error {
+	err := ghc.withRetry(func() (*github.Response, error) {
+		return ghc.client.Issues.RemoveLabelForIssue(ghc.ctx, githubOrg, githubRepo, issueId, label)
+	})
+	if err != nil {
+		if statusCode(err) == http.StatusNotFound {
+			return fmt.Errorf("%w: %w", ErrNotLabelable, err)
+		}
+		return fmt.Errorf("unable to remove label from issue #%d in %s/%s: %w", issueId, githubOrg, githubRepo, classifyError(err))
+	}
+	return nil
+}
+
+func (ghc *Client) FetchIssues(githubOrg, githubRepo, label string) (*Changelog, error) {
+	return ghc.FetchChangelog(githubOrg, githubRepo, label, DefaultCategories())
+}
+
+// FetchChangelog fetches the issues with the label and the pull requests linked to them,
+// and groups them by the categories.
+func (ghc *Client) FetchChangelog(githubOrg, githubRepo, label string, categories Categories) (*Changelog, error) {
+	issues, err := ghc.ListIssues(githubOrg, githubRepo, label)
+	if err != nil {
+		return nil, err
+	}
+
+	var numbers []int
+	for _, issue := range issues {
+		if !issue.IsPullRequest() {
+			numbers = append(numbers, issue.Number())
+		}
+	}
+	linkedPullRequests, err := ghc.FetchLinkedPullRequests(githubOrg, githubRepo, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	changelog, err := NewChangelogWithCategories(label, categories)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		issue.linkedPullRequests = linkedPullRequests[issue.Number()]
+		changelog.AddIssue(issue)
+	}
+
+	return changelog, nil
 }
 
-func (ghc *Client) FetchIssues(githubOrg, githubRepo, label string) *Changelog {
+// ListIssues returns all open and closed issues and pull requests which carry the label.
+func (ghc *Client) ListIssues(githubOrg, githubRepo, label string) ([]*Issue, error) {
 	options := &github.IssueListByRepoOptions{State: "all", Labels: []string{label}}
-	changelog := NewChangelog(label)
+	var result []*Issue
 
 	for {
-		issues, response, err := ghc.client.Issues.ListByRepo(ghc.ctx, githubOrg, githubRepo, options)
+		var issues []*github.Issue
+		var response *github.Response
+		err := ghc.withRetry(func() (*github.Response, error) {
+			var err error
+			issues, response, err = ghc.client.Issues.ListByRepo(ghc.ctx, githubOrg, githubRepo, options)
+			return response, err
+		})
 		if err != nil {
-			log.Fatalln(err)
+			return nil, fmt.Errorf("unable to fetch issues of %s/%s with label %q: %w", githubOrg, githubRepo, label, classifyError(err))
 		}
 
 		for _, issue := range issues {
-			changelog.AddIssue(NewIssue(issue))
+			result = append(result, NewIssue(issue))
 		}
 
 		if response.NextPage == 0 {
@@ -113,5 +204,5 @@ func (ghc *Client) FetchIssues(githubOrg, githubRepo, label string) *Changelog {
 		options.ListOptions.Page = response.NextPage
 	}
 
-	return changelog
+	return result, nil
 }
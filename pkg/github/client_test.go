@@ -3,11 +3,14 @@ package github
 import (
 	"bytes"
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -43,7 +46,10 @@ func TestAddLabel_404Error(t *testing.T) {
 	}
 
 	// Call AddLabel with a non-existent issue
-	ghc.AddLabel("testorg", "testrepo", 12345, "test-label")
+	err := ghc.AddLabel("testorg", "testrepo", 12345, "test-label")
+	if err != nil {
+		t.Errorf("Expected no error for missing issue, got: %v", err)
+	}
 
 	// Verify that a warning was logged instead of a fatal error
 	logOutput := buf.String()
@@ -81,7 +87,10 @@ func TestAddLabel_422MissingNodeWarns(t *testing.T) {
 		ctx:    context.Background(),
 	}
 
-	ghc.AddLabel("testorg", "testrepo", 49820, "test-label")
+	err := ghc.AddLabel("testorg", "testrepo", 49820, "test-label")
+	if err != nil {
+		t.Errorf("Expected no error for unprocessable issue, got: %v", err)
+	}
 
 	logOutput := buf.String()
 	if !strings.Contains(logOutput, "Warning: Issue #49820 could not be labeled") {
@@ -141,7 +150,9 @@ func TestAddLabel_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func(issueID int) {
 			defer wg.Done()
-			ghc.AddLabel("testorg", "testrepo", issueID, "test-label")
+			if err := ghc.AddLabel("testorg", "testrepo", issueID, "test-label"); err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
 		}(i)
 	}
 
@@ -186,7 +197,9 @@ func TestEnsureLabelExists_LabelAlreadyExists(t *testing.T) {
 		sleep:  func(_ time.Duration) {},
 	}
 
-	ghc.EnsureLabelExists("testorg", "testrepo", "test-label", false)
+	if err := ghc.EnsureLabelExists("testorg", "testrepo", "test-label", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	if getCalls != 1 {
 		t.Errorf("Expected 1 GET call, got %d", getCalls)
@@ -234,7 +247,9 @@ func TestEnsureLabelExists_CreatesAndVerifiesLabel(t *testing.T) {
 		sleep:  func(d time.Duration) { slept = d },
 	}
 
-	ghc.EnsureLabelExists("testorg", "testrepo", "test-label", false)
+	if err := ghc.EnsureLabelExists("testorg", "testrepo", "test-label", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	if getCalls != 2 {
 		t.Errorf("Expected 2 GET calls, got %d", getCalls)
@@ -310,7 +325,9 @@ func TestEnsureLabelExists_DryRunDoesNotCreateLabel(t *testing.T) {
 		sleep:  func(_ time.Duration) {},
 	}
 
-	ghc.EnsureLabelExists("testorg", "testrepo", "test-label", true)
+	if err := ghc.EnsureLabelExists("testorg", "testrepo", "test-label", true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	if getCalls != 1 {
 		t.Errorf("Expected 1 GET call, got %d", getCalls)
@@ -319,3 +336,459 @@ func TestEnsureLabelExists_DryRunDoesNotCreateLabel(t *testing.T) {
 		t.Errorf("Expected 0 POST calls in dry-run, got %d", postCalls)
 	}
 }
+
+func TestAddLabel_ClassifiesErrors(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+		headers    map[string]string
+		expected   error
+	}{
+		"Unauthorized":         {statusCode: http.StatusUnauthorized, expected: ErrPermissionDenied},
+		"Forbidden":            {statusCode: http.StatusForbidden, expected: ErrPermissionDenied},
+		"Primary rate limit":   {statusCode: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1"}, expected: ErrRateLimited},
+		"Secondary rate limit": {statusCode: http.StatusTooManyRequests, expected: ErrRateLimited},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tc.headers {
+					w.Header().Set(key, value)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(`{"message":"error"}`))
+			}))
+			defer server.Close()
+
+			err := newTestClient(server).AddLabel("testorg", "testrepo", 1, "test-label")
+
+			if !errors.Is(err, tc.expected) {
+				t.Errorf("Expected error %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestAddLabel_ServerErrorIsReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := newTestClient(server).AddLabel("testorg", "testrepo", 1, "test-label")
+
+	if err == nil || !strings.Contains(err.Error(), "unable to label issue #1 in testorg/testrepo") {
+		t.Errorf("Expected labeling error, got: %v", err)
+	}
+}
+
+func TestFetchIssues_RepositoryNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).FetchIssues("testorg", "testrepo", "test-label")
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
+
+func TestEnsureLabelExists_CreationFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Must have admin rights to Repository."}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+	ghc.sleep = func(_ time.Duration) {}
+
+	err := ghc.EnsureLabelExists("testorg", "testrepo", "test-label", false)
+
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected permission denied error, got: %v", err)
+	}
+}
+
+func TestAddLabel_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"name":"test-label"}]`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+	var sleeps []time.Duration
+	ghc.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+
+	err := ghc.AddLabel("testorg", "testrepo", 1, "test-label")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+	if len(sleeps) != 2 {
+		t.Fatalf("Expected 2 sleeps, got %v", sleeps)
+	}
+	if sleeps[0] < retryBaseDelay/2 || sleeps[0] > retryBaseDelay {
+		t.Errorf("Expected first backoff between %v and %v, got %v", retryBaseDelay/2, retryBaseDelay, sleeps[0])
+	}
+	if sleeps[1] < retryBaseDelay || sleeps[1] > 2*retryBaseDelay {
+		t.Errorf("Expected second backoff between %v and %v, got %v", retryBaseDelay, 2*retryBaseDelay, sleeps[1])
+	}
+}
+
+func TestAddLabel_GivesUpAfterMaxRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	err := ghc.AddLabel("testorg", "testrepo", 1, "test-label")
+
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if calls != defaultMaxRetries+1 {
+		t.Errorf("Expected %d calls, got %d", defaultMaxRetries+1, calls)
+	}
+}
+
+func TestAddLabel_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	if err := newTestClient(server).AddLabel("testorg", "testrepo", 1, "test-label"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestAddLabel_HonoursRetryAfter(t *testing.T) {
+	tests := map[string]struct {
+		body string
+	}{
+		"Secondary rate limit": {body: `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`},
+		"Too many requests":    {body: `{"message":"Too many requests"}`},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				if calls == 1 {
+					w.Header().Set("Retry-After", "30")
+					w.WriteHeader(http.StatusTooManyRequests)
+					w.Write([]byte(tc.body))
+					return
+				}
+				w.Write([]byte(`[{"name":"test-label"}]`))
+			}))
+			defer server.Close()
+
+			ghc := newTestClient(server)
+			// the go-github client blocks requests until the announced time itself
+			ghc.client.DisableRateLimitCheck = true
+			var slept time.Duration
+			ghc.sleep = func(d time.Duration) { slept += d }
+
+			if err := ghc.AddLabel("testorg", "testrepo", 1, "test-label"); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if slept != 30*time.Second {
+				t.Errorf("Expected to wait 30s, waited %v", slept)
+			}
+		})
+	}
+}
+
+func TestAddLabel_WaitsForRateLimitReset(t *testing.T) {
+	now := time.Now()
+	reset := now.Add(-time.Minute).Truncate(time.Second)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+			return
+		}
+		w.Write([]byte(`[{"name":"test-label"}]`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+	// pretend the reset is two minutes ahead, the real reset already passed so that the
+	// go-github client does not block the retry itself
+	ghc.now = func() time.Time { return reset.Add(-2 * time.Minute) }
+	var slept time.Duration
+	ghc.sleep = func(d time.Duration) { slept += d }
+
+	if err := ghc.AddLabel("testorg", "testrepo", 1, "test-label"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := 2*time.Minute + time.Second
+	if slept != expected {
+		t.Errorf("Expected to wait %v, waited %v", expected, slept)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		rate     github.Rate
+		expected time.Duration
+	}{
+		"Unknown rate":    {rate: github.Rate{}, expected: 0},
+		"Enough quota":    {rate: github.Rate{Limit: 5000, Remaining: 4000, Reset: github.Timestamp{Time: now.Add(time.Hour)}}, expected: 0},
+		"Low quota":       {rate: github.Rate{Limit: 5000, Remaining: 10, Reset: github.Timestamp{Time: now.Add(100 * time.Second)}}, expected: 10 * time.Second},
+		"Exhausted quota": {rate: github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: now.Add(100 * time.Second)}}, expected: 100 * time.Second},
+		"Reset passed":    {rate: github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: now.Add(-time.Second)}}, expected: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var slept time.Duration
+			ghc := &Client{
+				now:   func() time.Time { return now },
+				sleep: func(d time.Duration) { slept += d },
+				rate:  tc.rate,
+			}
+
+			ghc.Throttle()
+
+			if slept != tc.expected {
+				t.Errorf("Expected to wait %v, waited %v", tc.expected, slept)
+			}
+		})
+	}
+}
+
+func TestThrottle_ConcurrentWorkers(t *testing.T) {
+	now := time.Now()
+	workers := 10
+
+	var mutex sync.Mutex
+	var waits []time.Duration
+	ghc := &Client{
+		now: func() time.Time { return now },
+		sleep: func(d time.Duration) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			waits = append(waits, d)
+		},
+		rate: github.Rate{Limit: 5000, Remaining: 10, Reset: github.Timestamp{Time: now.Add(100 * time.Second)}},
+	}
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ghc.Throttle()
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(waits)
+	var expected []time.Duration
+	var total, expectedTotal time.Duration
+	for i := range workers {
+		expected = append(expected, time.Duration(i+1)*10*time.Second)
+		expectedTotal += expected[i]
+		total += waits[i]
+	}
+	if !slices.Equal(waits, expected) {
+		t.Errorf("Expected the workers to wait %v, waited %v", expected, waits)
+	}
+	if total != expectedTotal {
+		t.Errorf("Expected a total delay of %v, got %v", expectedTotal, total)
+	}
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	client := github.NewClient(nil)
+	baseURL, _ := url.Parse(server.URL + "/")
+	client.BaseURL = baseURL
+
+	return &Client{
+		client:     client,
+		ctx:        context.Background(),
+		sleep:      func(_ time.Duration) {},
+		now:        time.Now,
+		maxRetries: defaultMaxRetries,
+	}
+}
+
+func TestLabelIssue_NotLabelable(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+	}{
+		"Not found":     {statusCode: http.StatusNotFound},
+		"Unprocessable": {statusCode: http.StatusUnprocessableEntity},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(`{"message":"error"}`))
+			}))
+			defer server.Close()
+
+			err := newTestClient(server).LabelIssue("testorg", "testrepo", 1, "test-label")
+
+			if !errors.Is(err, ErrNotLabelable) {
+				t.Errorf("Expected error %v, got: %v", ErrNotLabelable, err)
+			}
+			if statusCode(err) != tc.statusCode {
+				t.Errorf("Expected status code %d in error, got: %v", tc.statusCode, err)
+			}
+		})
+	}
+}
+
+func TestRemoveLabel(t *testing.T) {
+	tests := map[string]struct {
+		statusCode int
+		expected   error
+	}{
+		"Removed":       {statusCode: http.StatusOK},
+		"Not labeled":   {statusCode: http.StatusNotFound, expected: ErrNotLabelable},
+		"No permission": {statusCode: http.StatusForbidden, expected: ErrPermissionDenied},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			err := newTestClient(server).RemoveLabel("testorg", "testrepo", 1, "version:8.6.0")
+
+			if tc.expected == nil && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if tc.expected != nil && !errors.Is(err, tc.expected) {
+				t.Errorf("Expected error %v, got: %v", tc.expected, err)
+			}
+			if len(requests) != 1 || requests[0] != "DELETE /repos/testorg/testrepo/issues/1/labels/version:8.6.0" {
+				t.Errorf("Expected a single DELETE request of the label, got: %v", requests)
+			}
+		})
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("labels") != "version:8.6.0" || r.URL.Query().Get("state") != "all" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"number":1,"title":"Crash","html_url":"https://github.com/testorg/testrepo/issues/1","state":"closed","state_reason":"not_planned"},
+			{"number":2,"title":"Feature","html_url":"https://github.com/testorg/testrepo/issues/2","state":"open"}
+		]`))
+	}))
+	defer server.Close()
+
+	issues, err := newTestClient(server).ListIssues("testorg", "testrepo", "version:8.6.0")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Number() != 1 || issues[0].Title() != "Crash" || issues[0].URL() != "https://github.com/testorg/testrepo/issues/1" {
+		t.Errorf("Unexpected issue %s", issues[0])
+	}
+	if issues[0].State() != "closed" || issues[0].StateReason() != "not_planned" {
+		t.Errorf("Expected closed as not planned, got %s (%s)", issues[0].State(), issues[0].StateReason())
+	}
+	if issues[1].State() != "open" || issues[1].StateReason() != "" {
+		t.Errorf("Expected open issue, got %s (%s)", issues[1].State(), issues[1].StateReason())
+	}
+}
+
+func TestResolveClosingIssues_RepositoryNotAccessible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","path":["repository"],"message":"Could not resolve to a Repository with the name 'testorg/testrepo'."}]}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).ResolveClosingIssues("testorg", "testrepo", []string{"abc"})
+
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "Could not resolve to a Repository") {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
+
+func TestResolveClosingIssues_RepositoryForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"FORBIDDEN","path":["repository"],"message":"Resource not accessible by integration"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).ResolveClosingIssues("testorg", "testrepo", []string{"abc"})
+
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected permission denied error, got: %v", err)
+	}
+}
+
+func TestResolveClosingIssues_NullRepositoryWithoutErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":null}}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).ResolveClosingIssues("testorg", "testrepo", []string{"abc"})
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v83/github"
+)
+
+var (
+	// ErrNotFound is returned if a repository, issue or label does not exist or is not
+	// visible with the used token.
+	ErrNotFound = errors.New("not found")
+	// ErrPermissionDenied is returned if the token is invalid or lacks the required scopes.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrRateLimited is returned if the primary or secondary rate limit of the GitHub API
+	// was exceeded.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrNotLabelable is returned if an issue cannot be labeled, because it does not exist
+	// or GitHub rejects the label, e.g. for transferred or deleted issues.
+	ErrNotLabelable = errors.New("issue cannot be labeled")
+)
+
+// classifyError wraps errors of the GitHub API with the matching error class, while
+// keeping the original error accessible through errors.As.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+
+	switch statusCode(err) {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+
+	return err
+}
+
+func statusCode(err error) int {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v83/github"
+)
+
+const (
+	graphQLRateLimitedType       = "RATE_LIMITED"
+	graphQLNotFoundType          = "NOT_FOUND"
+	graphQLForbiddenType         = "FORBIDDEN"
+	graphQLInsufficientScopeType = "INSUFFICIENT_SCOPES"
+)
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// graphQL runs the query against the GitHub GraphQL API and decodes the data of the
+// response into result. GitHub reports unresolvable aliased nodes, e.g. a missing issue
+// in a batch, as errors next to the partial data, these are tolerated. Errors of the
+// query itself or of a top level field, e.g. an inaccessible repository, are returned.
+func (ghc *Client) graphQL(query string, variables map[string]any, result any) error {
+	// queries are read-only and can be retried safely
+	var response graphQLResponse
+	err := ghc.withRetry(func() (*github.Response, error) {
+		request, err := ghc.client.NewRequest(http.MethodPost, "graphql", graphQLRequest{Query: query, Variables: variables})
+		if err != nil {
+			return nil, err
+		}
+
+		response = graphQLResponse{}
+		return ghc.client.Do(ghc.ctx, request, &response)
+	})
+	if err != nil {
+		return classifyError(err)
+	}
+
+	var fatal []graphQLError
+	for _, graphQLError := range response.Errors {
+		if len(graphQLError.Path) <= 1 {
+			fatal = append(fatal, graphQLError)
+		}
+	}
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		fatal = response.Errors
+	}
+	if len(fatal) > 0 {
+		return graphQLErrors(fatal)
+	}
+
+	return json.Unmarshal(response.Data, result)
+}
+
+// graphQLErrors combines the errors into one error, classified by the type of the
+// errors, e.g. ErrNotFound if the repository of the query does not exist.
+func graphQLErrors(graphQLErrors []graphQLError) error {
+	var messages []string
+	var class error
+	for _, graphQLError := range graphQLErrors {
+		messages = append(messages, graphQLError.Message)
+		if class != nil {
+			continue
+		}
+		switch graphQLError.Type {
+		case graphQLRateLimitedType:
+			class = ErrRateLimited
+		case graphQLNotFoundType:
+			class = ErrNotFound
+		case graphQLForbiddenType, graphQLInsufficientScopeType:
+			class = ErrPermissionDenied
+		}
+	}
+
+	if class != nil {
+		return fmt.Errorf("%w: GraphQL query failed: %s", class, strings.Join(messages, "; "))
+	}
+	return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+}
+
+// repositoryNotFound is returned if the repository of a query resolves to null, e.g.
+// because it does not exist or is not visible with the used token.
+func repositoryNotFound(owner, repo string) error {
+	return fmt.Errorf("%w: repository %s/%s does not exist or is not accessible", ErrNotFound, owner, repo)
+}
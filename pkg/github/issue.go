@@ -3,6 +3,7 @@ package github
 import (
 	"fmt"
 	"github.com/google/go-github/v83/github"
+	"sort"
 )
 
 const (
@@ -19,26 +20,19 @@ const (
 	supportLabel    = "support"
 )
 
-var knownLabels = []string{
-	brokerLabel,
-	gatewayLabel,
-	javaClientLabel,
-	goClientLabel,
-	featureLabel,
-	bugLabel,
-	docsLabel,
-	zbctlLabel,
-	toilLabel,
-	taskLabel,
-	supportLabel,
-}
-
 type Issue struct {
 	title       *string
 	number      *int
 	url         *string
+	state       string
+	stateReason string
+	author      string
+	issueType   string
+	milestone   string
 	labels      map[string]bool
 	pullRequest bool
+	// linkedPullRequests are the pull requests closing the issue, if they were fetched.
+	linkedPullRequests []LinkedPullRequest
 }
 
 func NewIssue(issue *github.Issue) *Issue {
@@ -46,6 +40,11 @@ func NewIssue(issue *github.Issue) *Issue {
 		title:       issue.Title,
 		number:      issue.Number,
 		url:         issue.HTMLURL,
+		state:       issue.GetState(),
+		stateReason: issue.GetStateReason(),
+		author:      issue.GetUser().GetLogin(),
+		issueType:   issue.GetType().GetName(),
+		milestone:   issue.GetMilestone().GetTitle(),
 		labels:      mapLabels(issue.Labels),
 		pullRequest: issue.IsPullRequest(),
 	}
@@ -54,12 +53,7 @@ func NewIssue(issue *github.Issue) *Issue {
 func mapLabels(labelList []*github.Label) map[string]bool {
 	labels := make(map[string]bool)
 	for _, label := range labelList {
-		labelName := label.GetName()
-		for _, knownLabel := range knownLabels {
-			if labelName == knownLabel {
-				labels[knownLabel] = true
-			}
-		}
+		labels[label.GetName()] = true
 	}
 	return labels
 }
@@ -108,6 +102,58 @@ func (i *Issue) hasLabel(label string) bool {
 	return i.labels[label]
 }
 
+func (i *Issue) Number() int {
+	return *i.number
+}
+
+func (i *Issue) Title() string {
+	return *i.title
+}
+
+func (i *Issue) URL() string {
+	return *i.url
+}
+
+// State returns whether the issue is "open" or "closed".
+func (i *Issue) State() string {
+	return i.state
+}
+
+// StateReason returns why the issue was closed, e.g. "completed" or "not_planned".
+func (i *Issue) StateReason() string {
+	return i.stateReason
+}
+
+// Author returns the login of the user who opened the issue.
+func (i *Issue) Author() string {
+	return i.author
+}
+
+// Type returns the name of the issue type, e.g. "Bug", or an empty string.
+func (i *Issue) Type() string {
+	return i.issueType
+}
+
+// Milestone returns the title of the milestone of the issue, or an empty string.
+func (i *Issue) Milestone() string {
+	return i.milestone
+}
+
+// Labels returns the names of all labels of the issue in alphabetical order.
+func (i *Issue) Labels() []string {
+	labels := make([]string, 0, len(i.labels))
+	for label := range i.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// LinkedPullRequests returns the pull requests which close the issue.
+func (i *Issue) LinkedPullRequests() []LinkedPullRequest {
+	return i.linkedPullRequests
+}
+
 func (i *Issue) IsPullRequest() bool {
 	return i.pullRequest
 }
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	issuesPerQuery = 50
+	labelsPerIssue = 100
+)
+
+// IssueLabels are the current labels of an issue or pull request.
+type IssueLabels struct {
+	Number int
+	Labels []string
+}
+
+// HasLabel reports whether the issue carries the label, labels are compared case
+// insensitive like on GitHub.
+func (il IssueLabels) HasLabel(label string) bool {
+	for _, name := range il.Labels {
+		if strings.EqualFold(name, label) {
+			return true
+		}
+	}
+	return false
+}
+
+type labelsNode struct {
+	Number int `json:"number"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// FetchIssueLabels fetches the current labels of the given issues and pull requests in
+// batches. Numbers which cannot be resolved to an issue or pull request are missing in
+// the returned map, an inaccessible repository is returned as error.
+func (ghc *Client) FetchIssueLabels(githubOrg, githubRepo string, numbers []int) (map[int]IssueLabels, error) {
+	issueLabels := make(map[int]IssueLabels, len(numbers))
+
+	for start := 0; start < len(numbers); start += issuesPerQuery {
+		end := min(start+issuesPerQuery, len(numbers))
+		batch := numbers[start:end]
+
+		var result struct {
+			Repository map[string]*labelsNode `json:"repository"`
+		}
+		if err := ghc.graphQL(issueLabelsQuery(batch), map[string]any{"owner": githubOrg, "repo": githubRepo}, &result); err != nil {
+			return nil, fmt.Errorf("unable to fetch labels of issues in %s/%s: %w", githubOrg, githubRepo, err)
+		}
+		if result.Repository == nil {
+			return nil, repositoryNotFound(githubOrg, githubRepo)
+		}
+
+		for i, number := range batch {
+			node := result.Repository[issueAlias(i)]
+			if node == nil {
+				continue
+			}
+
+			labels := IssueLabels{Number: number}
+			for _, label := range node.Labels.Nodes {
+				labels.Labels = append(labels.Labels, label.Name)
+			}
+			issueLabels[number] = labels
+		}
+	}
+
+	return issueLabels, nil
+}
+
+func issueLabelsQuery(numbers []int) string {
+	var b strings.Builder
+
+	b.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+	for i, number := range numbers {
+		b.WriteString(fmt.Sprintf(`    %s: issueOrPullRequest(number: %d) {
+      ... on Issue { number labels(first: %d) { nodes { name } } }
+      ... on PullRequest { number labels(first: %d) { nodes { name } } }
+    }
+`, issueAlias(i), number, labelsPerIssue, labelsPerIssue))
+	}
+	b.WriteString("  }\n}\n")
+
+	return b.String()
+}
+
+func issueAlias(index int) string {
+	return fmt.Sprintf("i%d", index)
+}
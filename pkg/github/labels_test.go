@@ -0,0 +1,90 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchIssueLabels(t *testing.T) {
+	var queries []graphQLRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		queries = append(queries, request)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{
+			"i0":{"number":1,"labels":{"nodes":[{"name":"kind/bug"},{"name":"Version:8.6.0"}]}},
+			"i1":{"number":2,"labels":{"nodes":[]}},
+			"i2":null
+		}},"errors":[{"type":"NOT_FOUND","path":["repository","i2"],"message":"Could not resolve to an issue or pull request with the number of 3."}]}`))
+	}))
+	defer server.Close()
+
+	labels, err := newTestClient(server).FetchIssueLabels("testorg", "testrepo", []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.True(t, labels[1].HasLabel("version:8.6.0"))
+	assert.False(t, labels[2].HasLabel("version:8.6.0"))
+	_, found := labels[3]
+	assert.False(t, found)
+
+	assert.Len(t, queries, 1)
+	assert.Contains(t, queries[0].Query, "i0: issueOrPullRequest(number: 1)")
+	assert.Contains(t, queries[0].Query, "i2: issueOrPullRequest(number: 3)")
+}
+
+func TestFetchIssueLabels_BatchesIssues(t *testing.T) {
+	queries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{}}}`))
+	}))
+	defer server.Close()
+
+	numbers := make([]int, 2*issuesPerQuery+1)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	labels, err := newTestClient(server).FetchIssueLabels("testorg", "testrepo", numbers)
+
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+	assert.Equal(t, 3, queries)
+}
+
+func TestFetchIssueLabels_RepositoryNotAccessible(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","path":["repository"],"message":"Could not resolve to a Repository with the name 'testorg/testrepo'."}]}`))
+	}))
+	defer server.Close()
+
+	labels, err := newTestClient(server).FetchIssueLabels("testorg", "testrepo", []int{1, 2})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, labels)
+}
+
+func TestFetchIssueLabels_NullRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":null}}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).FetchIssueLabels("testorg", "testrepo", []int{1})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "testorg/testrepo")
+}
@@ -0,0 +1,250 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	commitsPerQuery         = 25
+	pullRequestsPerCommit   = 5
+	closingIssuesPerRequest = 50
+	pullRequestsPerQuery    = 50
+	linkedPullRequestsLimit = 10
+)
+
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	// HeadRefName is the name of the branch which was merged.
+	HeadRefName         string `json:"headRefName"`
+	HeadRepositoryOwner struct {
+		Login string `json:"login"`
+	} `json:"headRepositoryOwner"`
+}
+
+// HeadBranch returns the branch which was merged in owner/branch notation, like the
+// subject of merge commits names it.
+func (pr PullRequest) HeadBranch() string {
+	if pr.HeadRepositoryOwner.Login == "" {
+		return pr.HeadRefName
+	}
+	return pr.HeadRepositoryOwner.Login + "/" + pr.HeadRefName
+}
+
+// LinkedPullRequest is a pull request which closes an issue, as shown in the "Development"
+// sidebar of the issue.
+type LinkedPullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+}
+
+// ClosingIssue is an issue which is closed by a merged pull request associated with a
+// commit. The pull request itself is returned as ClosingIssue of the commit as well, so
+// that it can be labeled like pull requests referenced in merge commits.
+type ClosingIssue struct {
+	SHA         string
+	PullRequest int
+	Owner       string
+	Repo        string
+	Number      int
+}
+
+type associatedPullRequests struct {
+	AssociatedPullRequests struct {
+		Nodes []struct {
+			Number                  int  `json:"number"`
+			Merged                  bool `json:"merged"`
+			ClosingIssuesReferences struct {
+				Nodes []struct {
+					Number     int `json:"number"`
+					Repository struct {
+						Name  string `json:"name"`
+						Owner struct {
+							Login string `json:"login"`
+						} `json:"owner"`
+					} `json:"repository"`
+				} `json:"nodes"`
+			} `json:"closingIssuesReferences"`
+		} `json:"nodes"`
+	} `json:"associatedPullRequests"`
+}
+
+// ResolveClosingIssues looks up the merged pull requests associated with the given
+// commits and the issues these pull requests close, as shown in the "Development"
+// sidebar of the pull request.
+func (ghc *Client) ResolveClosingIssues(githubOrg, githubRepo string, shas []string) ([]ClosingIssue, error) {
+	var closingIssues []ClosingIssue
+
+	for start := 0; start < len(shas); start += commitsPerQuery {
+		end := min(start+commitsPerQuery, len(shas))
+		batch := shas[start:end]
+
+		var result struct {
+			Repository map[string]*associatedPullRequests `json:"repository"`
+		}
+		if err := ghc.graphQL(closingIssuesQuery(batch), map[string]any{"owner": githubOrg, "repo": githubRepo}, &result); err != nil {
+			return nil, fmt.Errorf("unable to resolve pull requests of commits in %s/%s: %w", githubOrg, githubRepo, err)
+		}
+		if result.Repository == nil {
+			return nil, repositoryNotFound(githubOrg, githubRepo)
+		}
+
+		for i, sha := range batch {
+			commit := result.Repository[commitAlias(i)]
+			if commit == nil {
+				continue
+			}
+
+			for _, pullRequest := range commit.AssociatedPullRequests.Nodes {
+				if !pullRequest.Merged {
+					continue
+				}
+
+				closingIssues = append(closingIssues, ClosingIssue{
+					SHA:         sha,
+					PullRequest: pullRequest.Number,
+					Owner:       githubOrg,
+					Repo:        githubRepo,
+					Number:      pullRequest.Number,
+				})
+				for _, issue := range pullRequest.ClosingIssuesReferences.Nodes {
+					closingIssues = append(closingIssues, ClosingIssue{
+						SHA:         sha,
+						PullRequest: pullRequest.Number,
+						Owner:       issue.Repository.Owner.Login,
+						Repo:        issue.Repository.Name,
+						Number:      issue.Number,
+					})
+				}
+			}
+		}
+	}
+
+	return closingIssues, nil
+}
+
+func closingIssuesQuery(shas []string) string {
+	var b strings.Builder
+
+	b.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+	for i, sha := range shas {
+		b.WriteString(fmt.Sprintf(`    %s: object(oid: %q) {
+      ... on Commit {
+        associatedPullRequests(first: %d) {
+          nodes {
+            number
+            merged
+            closingIssuesReferences(first: %d) {
+              nodes { number repository { name owner { login } } }
+            }
+          }
+        }
+      }
+    }
+`, commitAlias(i), sha, pullRequestsPerCommit, closingIssuesPerRequest))
+	}
+	b.WriteString("  }\n}\n")
+
+	return b.String()
+}
+
+func commitAlias(index int) string {
+	return fmt.Sprintf("c%d", index)
+}
+
+// FetchPullRequests fetches the title, body and head branch of the given pull requests. Numbers
+// which cannot be resolved to a pull request are skipped.
+func (ghc *Client) FetchPullRequests(githubOrg, githubRepo string, numbers []int) ([]PullRequest, error) {
+	var pullRequests []PullRequest
+
+	for start := 0; start < len(numbers); start += pullRequestsPerQuery {
+		end := min(start+pullRequestsPerQuery, len(numbers))
+		batch := numbers[start:end]
+
+		var result struct {
+			Repository map[string]*PullRequest `json:"repository"`
+		}
+		if err := ghc.graphQL(pullRequestsQuery(batch), map[string]any{"owner": githubOrg, "repo": githubRepo}, &result); err != nil {
+			return nil, fmt.Errorf("unable to fetch pull requests of %s/%s: %w", githubOrg, githubRepo, err)
+		}
+		if result.Repository == nil {
+			return nil, repositoryNotFound(githubOrg, githubRepo)
+		}
+
+		for i := range batch {
+			if pullRequest := result.Repository[pullRequestAlias(i)]; pullRequest != nil {
+				pullRequests = append(pullRequests, *pullRequest)
+			}
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func pullRequestsQuery(numbers []int) string {
+	var b strings.Builder
+
+	b.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+	for i, number := range numbers {
+		b.WriteString(fmt.Sprintf("    %s: pullRequest(number: %d) { number title body headRefName headRepositoryOwner { login } }\n", pullRequestAlias(i), number))
+	}
+	b.WriteString("  }\n}\n")
+
+	return b.String()
+}
+
+func pullRequestAlias(index int) string {
+	return fmt.Sprintf("p%d", index)
+}
+
+type linkedPullRequestsNode struct {
+	ClosedByPullRequestsReferences struct {
+		Nodes []LinkedPullRequest `json:"nodes"`
+	} `json:"closedByPullRequestsReferences"`
+}
+
+// FetchLinkedPullRequests fetches the pull requests which close the given issues in
+// batches. Issues without linked pull requests or which cannot be resolved are missing
+// in the returned map.
+func (ghc *Client) FetchLinkedPullRequests(githubOrg, githubRepo string, numbers []int) (map[int][]LinkedPullRequest, error) {
+	linkedPullRequests := make(map[int][]LinkedPullRequest)
+
+	for start := 0; start < len(numbers); start += issuesPerQuery {
+		end := min(start+issuesPerQuery, len(numbers))
+		batch := numbers[start:end]
+
+		var result struct {
+			Repository map[string]*linkedPullRequestsNode `json:"repository"`
+		}
+		if err := ghc.graphQL(linkedPullRequestsQuery(batch), map[string]any{"owner": githubOrg, "repo": githubRepo}, &result); err != nil {
+			return nil, fmt.Errorf("unable to fetch linked pull requests of issues in %s/%s: %w", githubOrg, githubRepo, err)
+		}
+		if result.Repository == nil {
+			return nil, repositoryNotFound(githubOrg, githubRepo)
+		}
+
+		for i, number := range batch {
+			if node := result.Repository[issueAlias(i)]; node != nil && len(node.ClosedByPullRequestsReferences.Nodes) > 0 {
+				linkedPullRequests[number] = node.ClosedByPullRequestsReferences.Nodes
+			}
+		}
+	}
+
+	return linkedPullRequests, nil
+}
+
+func linkedPullRequestsQuery(numbers []int) string {
+	var b strings.Builder
+
+	b.WriteString("query($owner: String!, $repo: String!) {\n  repository(owner: $owner, name: $repo) {\n")
+	for i, number := range numbers {
+		b.WriteString(fmt.Sprintf("    %s: issue(number: %d) { closedByPullRequestsReferences(first: %d) { nodes { number title url } } }\n",
+			issueAlias(i), number, linkedPullRequestsLimit))
+	}
+	b.WriteString("  }\n}\n")
+
+	return b.String()
+}
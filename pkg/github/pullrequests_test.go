@@ -0,0 +1,157 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveClosingIssues(t *testing.T) {
+	var queries []graphQLRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		queries = append(queries, request)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{
+			"c0":{"associatedPullRequests":{"nodes":[
+				{"number":10,"merged":true,"closingIssuesReferences":{"nodes":[
+					{"number":1,"repository":{"name":"testrepo","owner":{"login":"testorg"}}},
+					{"number":412,"repository":{"name":"connectors","owner":{"login":"testorg"}}}
+				]}},
+				{"number":11,"merged":false,"closingIssuesReferences":{"nodes":[
+					{"number":2,"repository":{"name":"testrepo","owner":{"login":"testorg"}}}
+				]}}
+			]}},
+			"c1":null
+		}},"errors":[{"type":"NOT_FOUND","path":["repository","c1"],"message":"Could not resolve to a node"}]}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	closingIssues, err := ghc.ResolveClosingIssues("testorg", "testrepo", []string{"abc", "def"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ClosingIssue{
+		{SHA: "abc", PullRequest: 10, Owner: "testorg", Repo: "testrepo", Number: 10},
+		{SHA: "abc", PullRequest: 10, Owner: "testorg", Repo: "testrepo", Number: 1},
+		{SHA: "abc", PullRequest: 10, Owner: "testorg", Repo: "connectors", Number: 412},
+	}, closingIssues)
+
+	assert.Len(t, queries, 1)
+	assert.Equal(t, map[string]any{"owner": "testorg", "repo": "testrepo"}, queries[0].Variables)
+	assert.Contains(t, queries[0].Query, `c0: object(oid: "abc")`)
+	assert.Contains(t, queries[0].Query, `c1: object(oid: "def")`)
+}
+
+func TestResolveClosingIssues_BatchesCommits(t *testing.T) {
+	queries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{}}}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	shas := make([]string, commitsPerQuery+1)
+	for i := range shas {
+		shas[i] = strings.Repeat("a", i+1)
+	}
+	closingIssues, err := ghc.ResolveClosingIssues("testorg", "testrepo", shas)
+
+	assert.NoError(t, err)
+	assert.Empty(t, closingIssues)
+	assert.Equal(t, 2, queries)
+}
+
+func TestResolveClosingIssues_QueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":null,"errors":[{"message":"Something went wrong"}]}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	_, err := ghc.ResolveClosingIssues("testorg", "testrepo", []string{"abc"})
+
+	assert.ErrorContains(t, err, "Something went wrong")
+}
+
+func TestFetchPullRequests(t *testing.T) {
+	var query string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		query = request.Query
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{
+			"p0":{"number":10,"title":"fix: timeouts","body":"closes #1\r\n","headRefName":"fix-timeouts","headRepositoryOwner":{"login":"camunda"}},
+			"p1":null
+		}},"errors":[{"type":"NOT_FOUND","path":["repository","p1"],"message":"Could not resolve to a PullRequest with the number of 11."}]}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	pullRequests, err := ghc.FetchPullRequests("testorg", "testrepo", []int{10, 11})
+
+	assert.NoError(t, err)
+	assert.Len(t, pullRequests, 1)
+	assert.Equal(t, 10, pullRequests[0].Number)
+	assert.Equal(t, "fix: timeouts", pullRequests[0].Title)
+	assert.Equal(t, "closes #1\r\n", pullRequests[0].Body)
+	assert.Equal(t, "camunda/fix-timeouts", pullRequests[0].HeadBranch())
+	assert.Contains(t, query, "p0: pullRequest(number: 10)")
+	assert.Contains(t, query, "p1: pullRequest(number: 11)")
+}
+
+func TestFetchLinkedPullRequests(t *testing.T) {
+	var query string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		query = request.Query
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"repository":{
+			"i0":{"closedByPullRequestsReferences":{"nodes":[{"number":10,"title":"fix: timeouts","url":"u10"}]}},
+			"i1":{"closedByPullRequestsReferences":{"nodes":[]}},
+			"i2":null
+		}},"errors":[{"type":"NOT_FOUND","path":["repository","i2"],"message":"Could not resolve to an Issue with the number of 3."}]}`))
+	}))
+	defer server.Close()
+
+	ghc := newTestClient(server)
+
+	linkedPullRequests, err := ghc.FetchLinkedPullRequests("testorg", "testrepo", []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]LinkedPullRequest{1: {{Number: 10, Title: "fix: timeouts", URL: "u10"}}}, linkedPullRequests)
+	assert.Contains(t, query, "i0: issue(number: 1)")
+	assert.Contains(t, query, "i2: issue(number: 3)")
+}
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v83/github"
+)
+
+// ErrReleasePublished is returned if the release of a tag was already published, since
+// the notes of published releases are not overwritten.
+var ErrReleasePublished = errors.New("release is already published")
+
+// Release is a GitHub release, which is a draft until it is published.
+type Release struct {
+	ID    int64
+	Tag   string
+	Name  string
+	Body  string
+	Draft bool
+	URL   string
+}
+
+func newRelease(release *github.RepositoryRelease) *Release {
+	return &Release{
+		ID:    release.GetID(),
+		Tag:   release.GetTagName(),
+		Name:  release.GetName(),
+		Body:  release.GetBody(),
+		Draft: release.GetDraft(),
+		URL:   release.GetHTMLURL(),
+	}
+}
+
+// FindRelease returns the release of the tag, or nil if there is none. Releases are
+// listed instead of looked up by tag, since draft releases cannot be looked up by tag.
+// Drafts are preferred if there are several releases of the tag.
+func (ghc *Client) FindRelease(githubOrg, githubRepo, tag string) (*Release, error) {
+	options := &github.ListOptions{PerPage: 100}
+	var found *Release
+
+	for {
+		var releases []*github.RepositoryRelease
+		var response *github.Response
+		err := ghc.withRetry(func() (*github.Response, error) {
+			var err error
+			releases, response, err = ghc.client.Repositories.ListReleases(ghc.ctx, githubOrg, githubRepo, options)
+			return response, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list releases of %s/%s: %w", githubOrg, githubRepo, classifyError(err))
+		}
+
+		for _, release := range releases {
+			if release.GetTagName() != tag {
+				continue
+			}
+			if release.GetDraft() {
+				return newRelease(release), nil
+			}
+			if found == nil {
+				found = newRelease(release)
+			}
+		}
+
+		if response.NextPage == 0 {
+			return found, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// CreateDraftRelease creates a draft release of the tag, named like the tag. The request
+// is not retried, since retrying could create several drafts.
+func (ghc *Client) CreateDraftRelease(githubOrg, githubRepo, tag, body string) (*Release, error) {
+	release, _, err := ghc.client.Repositories.CreateRelease(ghc.ctx, githubOrg, githubRepo, &github.RepositoryRelease{
+		TagName: github.Ptr(tag),
+		Name:    github.Ptr(tag),
+		Body:    github.Ptr(body),
+		Draft:   github.Ptr(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create draft release %s in %s/%s: %w", tag, githubOrg, githubRepo, classifyError(err))
+	}
+	return newRelease(release), nil
+}
+
+// UpdateReleaseBody replaces the notes of the release.
+func (ghc *Client) UpdateReleaseBody(githubOrg, githubRepo string, id int64, body string) (*Release, error) {
+	var release *github.RepositoryRelease
+	err := ghc.withRetry(func() (*github.Response, error) {
+		var response *github.Response
+		var err error
+		release, response, err = ghc.client.Repositories.EditRelease(ghc.ctx, githubOrg, githubRepo, id, &github.RepositoryRelease{
+			Body: github.Ptr(body),
+		})
+		return response, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to update release %d in %s/%s: %w", id, githubOrg, githubRepo, classifyError(err))
+	}
+	return newRelease(release), nil
+}
+
+// PublishReleaseNotes writes the body to the draft release of the tag, which is created
+// if it does not exist. Published releases are not changed and fail with
+// ErrReleasePublished.
+func (ghc *Client) PublishReleaseNotes(githubOrg, githubRepo, tag, body string) (*Release, error) {
+	release, err := ghc.FindRelease(githubOrg, githubRepo, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if release == nil {
+		return ghc.CreateDraftRelease(githubOrg, githubRepo, tag, body)
+	}
+	if !release.Draft {
+		return nil, fmt.Errorf("%w: %s", ErrReleasePublished, release.URL)
+	}
+	if NormalizeLineEndings(release.Body) == NormalizeLineEndings(body) {
+		return release, nil
+	}
+	return ghc.UpdateReleaseBody(githubOrg, githubRepo, release.ID, body)
+}
+
+// NormalizeLineEndings replaces the carriage returns GitHub stores for release notes
+// edited in the browser, so that notes are compared by their content.
+func NormalizeLineEndings(body string) string {
+	return strings.ReplaceAll(body, "\r\n", "\n")
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/testorg/testrepo/releases?page=2>; rel="next"`, r.Host))
+			w.Write([]byte(`[{"id":1,"tag_name":"8.5.0","draft":false},{"id":2,"tag_name":"8.6.0","draft":false,"html_url":"u2"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":3,"tag_name":"8.6.0","name":"8.6.0","body":"notes","draft":true,"html_url":"u3"}]`))
+	}))
+	defer server.Close()
+
+	release, err := newTestClient(server).FindRelease("testorg", "testrepo", "8.6.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Release{ID: 3, Tag: "8.6.0", Name: "8.6.0", Body: "notes", Draft: true, URL: "u3"}, release)
+}
+
+func TestFindRelease_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"tag_name":"8.5.0","draft":false}]`))
+	}))
+	defer server.Close()
+
+	release, err := newTestClient(server).FindRelease("testorg", "testrepo", "8.6.0")
+
+	assert.NoError(t, err)
+	assert.Nil(t, release)
+}
+
+func TestPublishReleaseNotes(t *testing.T) {
+	tests := map[string]struct {
+		releases       string
+		expectedMethod string
+		expectedPath   string
+	}{
+		"Create draft": {
+			releases:       `[]`,
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/repos/testorg/testrepo/releases",
+		},
+		"Update draft": {
+			releases:       `[{"id":3,"tag_name":"8.6.0","body":"old","draft":true}]`,
+			expectedMethod: http.MethodPatch,
+			expectedPath:   "/repos/testorg/testrepo/releases/3",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var method, path string
+			var request map[string]any
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodGet {
+					w.Write([]byte(tc.releases))
+					return
+				}
+
+				method, path = r.Method, r.URL.Path
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &request); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+				w.Write([]byte(`{"id":3,"tag_name":"8.6.0","body":"new","draft":true,"html_url":"u3"}`))
+			}))
+			defer server.Close()
+
+			release, err := newTestClient(server).PublishReleaseNotes("testorg", "testrepo", "8.6.0", "new")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "u3", release.URL)
+			assert.Equal(t, tc.expectedMethod, method)
+			assert.Equal(t, tc.expectedPath, path)
+			assert.Equal(t, "new", request["body"])
+			if method == http.MethodPost {
+				assert.Equal(t, true, request["draft"])
+				assert.Equal(t, "8.6.0", request["tag_name"])
+			}
+		})
+	}
+}
+
+func TestPublishReleaseNotes_Unchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected no update of the unchanged release, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3,"tag_name":"8.6.0","body":"notes","draft":true}]`))
+	}))
+	defer server.Close()
+
+	release, err := newTestClient(server).PublishReleaseNotes("testorg", "testrepo", "8.6.0", "notes")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), release.ID)
+}
+
+func TestPublishReleaseNotes_UnchangedWithCarriageReturns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected no update of the unchanged release, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3,"tag_name":"8.6.0","body":"# Notes\r\n\r\n* fix\r\n","draft":true}]`))
+	}))
+	defer server.Close()
+
+	release, err := newTestClient(server).PublishReleaseNotes("testorg", "testrepo", "8.6.0", "# Notes\n\n* fix\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), release.ID)
+}
+
+func TestPublishReleaseNotes_Published(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected no update of the published release, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3,"tag_name":"8.6.0","body":"notes","draft":false,"html_url":"u3"}]`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).PublishReleaseNotes("testorg", "testrepo", "8.6.0", "new")
+
+	assert.True(t, errors.Is(err, ErrReleasePublished), "unexpected error: %v", err)
+	assert.ErrorContains(t, err, "u3")
+}
+
+func TestCreateDraftRelease_PermissionDenied(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(server).CreateDraftRelease("testorg", "testrepo", "8.6.0", "notes")
+
+	assert.True(t, errors.Is(err, ErrPermissionDenied), "unexpected error: %v", err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v83/github"
+)
+
+const (
+	defaultMaxRetries = 5
+	retryBaseDelay    = time.Second
+	retryMaxDelay     = time.Minute
+	// rateLimitThreshold is the number of remaining requests below which Throttle spreads
+	// the remaining requests over the time until the rate limit resets.
+	rateLimitThreshold = 100
+)
+
+// withRetry runs the idempotent call and retries it on server errors, network errors and
+// exceeded rate limits. Rate limits are waited out as announced by the Retry-After and
+// X-RateLimit-Reset headers, other failures are retried with exponential backoff.
+func (ghc *Client) withRetry(call func() (*github.Response, error)) error {
+	for attempt := 0; ; attempt++ {
+		response, err := call()
+		ghc.updateRate(response)
+		if err == nil {
+			return nil
+		}
+
+		delay, retryable := ghc.retryDelay(err, attempt)
+		if !retryable || attempt >= ghc.maxRetries {
+			return err
+		}
+
+		log.Printf("Warning: GitHub request failed, retrying in %s (%d/%d): %v\n", delay.Round(time.Millisecond), attempt+1, ghc.maxRetries, err)
+		ghc.sleep(delay)
+	}
+}
+
+func (ghc *Client) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseRateLimitErr) {
+		if retryAfter := abuseRateLimitErr.GetRetryAfter(); retryAfter > 0 {
+			return retryAfter, true
+		}
+		return backoff(attempt), true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if wait := rateLimitErr.Rate.Reset.Sub(ghc.now()); wait > 0 {
+			return wait + time.Second, true
+		}
+		return backoff(attempt), true
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) {
+		if errResp.Response == nil {
+			return 0, false
+		}
+
+		code := errResp.Response.StatusCode
+		if code != http.StatusTooManyRequests && code < http.StatusInternalServerError {
+			return 0, false
+		}
+		if retryAfter, ok := parseRetryAfter(errResp.Response.Header.Get("Retry-After")); ok {
+			return retryAfter, true
+		}
+		return backoff(attempt), true
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	// network errors, e.g. connection resets or timeouts
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// backoff returns an exponentially growing delay with jitter, so that concurrent workers
+// do not retry in lockstep.
+func backoff(attempt int) time.Duration {
+	delay := retryMaxDelay
+	if attempt < 16 {
+		delay = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func (ghc *Client) updateRate(response *github.Response) {
+	if response == nil || response.Rate.Limit == 0 {
+		return
+	}
+
+	ghc.rateMutex.Lock()
+	defer ghc.rateMutex.Unlock()
+	ghc.rate = response.Rate
+}
+
+// Throttle blocks if only few requests remain before the rate limit resets, spreading
+// the remaining requests evenly over the time until the reset. Every call reserves the
+// next free slot, so that the requests of concurrent workers are spread as well.
+func (ghc *Client) Throttle() {
+	ghc.rateMutex.Lock()
+	rate := ghc.rate
+	now := ghc.now()
+
+	if rate.Limit == 0 || rate.Remaining >= rateLimitThreshold {
+		ghc.rateMutex.Unlock()
+		return
+	}
+
+	untilReset := rate.Reset.Sub(now)
+	if untilReset <= 0 {
+		ghc.rateMutex.Unlock()
+		return
+	}
+
+	slot := ghc.nextRequest
+	if slot.Before(now) {
+		slot = now
+	}
+	slot = slot.Add(untilReset / time.Duration(max(rate.Remaining, 1)))
+	ghc.nextRequest = slot
+	ghc.rateMutex.Unlock()
+
+	ghc.sleep(slot.Sub(now))
+}
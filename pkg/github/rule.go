@@ -0,0 +1,290 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	isOperator      = "is"
+	matchesOperator = "matches"
+)
+
+// ruleFields are the issue fields rules can compare with the "is" and "matches" operators.
+var ruleFields = map[string]func(issue *Issue) []string{
+	"label":     func(issue *Issue) []string { return issue.Labels() },
+	"title":     func(issue *Issue) []string { return []string{issue.Title()} },
+	"state":     func(issue *Issue) []string { return []string{issue.State()} },
+	"reason":    func(issue *Issue) []string { return []string{issue.StateReason()} },
+	"author":    func(issue *Issue) []string { return []string{issue.Author()} },
+	"type":      func(issue *Issue) []string { return []string{issue.Type()} },
+	"milestone": func(issue *Issue) []string { return []string{issue.Milestone()} },
+}
+
+// Rule is a boolean expression over the fields of an issue, used to assign issues to the
+// chapters and sections of a changelog. A bare word matches issues with the label of that
+// name, fields are compared with "is" or "matches" (regular expression), and expressions
+// can be combined with AND, OR, NOT and parentheses. Label names and "is" are case
+// insensitive:
+//
+//	kind/bug AND NOT support
+//	component/zeebe OR component/gateway
+//	title matches "^\[Security\]"
+//	state is closed AND NOT reason is not_planned
+//
+// Values containing spaces or parentheses are quoted with double quotes.
+type Rule struct {
+	source string
+	root   ruleNode
+}
+
+// ParseRule parses a rule expression.
+func ParseRule(source string) (*Rule, error) {
+	tokens, err := tokenizeRule(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", source, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid rule %q: empty expression", source)
+	}
+
+	parser := &ruleParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err == nil && !parser.done() {
+		err = fmt.Errorf("unexpected %s", parser.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", source, err)
+	}
+
+	return &Rule{source: source, root: root}, nil
+}
+
+// Matches reports whether the issue fulfills the rule.
+func (r *Rule) Matches(issue *Issue) bool {
+	return r.root.matches(issue)
+}
+
+func (r *Rule) String() string {
+	return r.source
+}
+
+type ruleNode interface {
+	matches(issue *Issue) bool
+}
+
+type andNode struct{ left, right ruleNode }
+
+func (n andNode) matches(issue *Issue) bool { return n.left.matches(issue) && n.right.matches(issue) }
+
+type orNode struct{ left, right ruleNode }
+
+func (n orNode) matches(issue *Issue) bool { return n.left.matches(issue) || n.right.matches(issue) }
+
+type notNode struct{ node ruleNode }
+
+func (n notNode) matches(issue *Issue) bool { return !n.node.matches(issue) }
+
+// labelNode matches issues with the label, label names are case insensitive like on
+// GitHub.
+type labelNode struct{ label string }
+
+func (n labelNode) matches(issue *Issue) bool {
+	return fieldNode{values: ruleFields["label"], value: n.label}.matches(issue)
+}
+
+// fieldNode compares a field with a value, fields with several values like labels match
+// if any value matches.
+type fieldNode struct {
+	values func(issue *Issue) []string
+	value  string
+	regex  *regexp.Regexp
+}
+
+func (n fieldNode) matches(issue *Issue) bool {
+	for _, value := range n.values(issue) {
+		if n.regex != nil && n.regex.MatchString(value) {
+			return true
+		}
+		if n.regex == nil && strings.EqualFold(value, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+type ruleToken struct {
+	text   string
+	quoted bool
+}
+
+func (t ruleToken) String() string {
+	if t.quoted {
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+func (t ruleToken) is(text string) bool {
+	return !t.quoted && strings.EqualFold(t.text, text)
+}
+
+func (t ruleToken) isKeyword() bool {
+	return t.is("AND") || t.is("OR") || t.is("NOT") || t.is("(") || t.is(")")
+}
+
+func tokenizeRule(source string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, ruleToken{text: string(r)})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				// only quotes and backslashes are escaped, so that regular expressions
+				// can be written as usual
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, errors.New("unterminated quoted value")
+			}
+			i++
+			tokens = append(tokens, ruleToken{text: b.String(), quoted: true})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, ruleToken{text: string(runes[start:i])})
+		}
+	}
+
+	return tokens, nil
+}
+
+type ruleParser struct {
+	tokens   []ruleToken
+	position int
+}
+
+func (p *ruleParser) done() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *ruleParser) peek() ruleToken {
+	return p.tokens[p.position]
+}
+
+func (p *ruleParser) next() (ruleToken, error) {
+	if p.done() {
+		return ruleToken{}, errors.New("unexpected end of expression")
+	}
+	token := p.peek()
+	p.position++
+	return token, nil
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().is("OR") {
+		p.position++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for !p.done() && p.peek().is("AND") {
+		p.position++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if !p.done() && p.peek().is("NOT") {
+		p.position++
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *ruleParser) parsePrimary() (ruleNode, error) {
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.is("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.next()
+		if err != nil {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		if !closing.is(")") {
+			return nil, fmt.Errorf("expected closing parenthesis, got %s", closing)
+		}
+		return node, nil
+	}
+	if token.isKeyword() {
+		return nil, fmt.Errorf("unexpected %s", token)
+	}
+
+	values, isField := ruleFields[strings.ToLower(token.text)]
+	if token.quoted || !isField || p.done() || !(p.peek().is(isOperator) || p.peek().is(matchesOperator)) {
+		return labelNode{label: token.text}, nil
+	}
+
+	operator, _ := p.next()
+	value, err := p.next()
+	if err != nil {
+		return nil, fmt.Errorf("missing value after %s %s", token, operator)
+	}
+	if value.isKeyword() {
+		return nil, fmt.Errorf("missing value after %s %s, got %s", token, operator, value)
+	}
+
+	node := fieldNode{values: values, value: value.text}
+	if operator.is(matchesOperator) {
+		node.regex, err = regexp.Compile(value.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value.text, err)
+		}
+	}
+	return node, nil
+}
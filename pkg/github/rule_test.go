@@ -0,0 +1,80 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v83/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Matches(t *testing.T) {
+	issue := NewIssue(&github.Issue{
+		Title:       github.Ptr("[Security] Update dependency"),
+		Number:      github.Ptr(1),
+		HTMLURL:     github.Ptr("u1"),
+		State:       github.Ptr("closed"),
+		StateReason: github.Ptr("completed"),
+		User:        &github.User{Login: github.Ptr("octocat")},
+		Type:        &github.IssueType{Name: github.Ptr("Bug")},
+		Milestone:   &github.Milestone{Title: github.Ptr("8.6.0")},
+		Labels:      []*github.Label{{Name: github.Ptr("kind/bug")}, {Name: github.Ptr("component/zeebe")}},
+	})
+
+	tests := map[string]bool{
+		"kind/bug":                                true,
+		"Kind/Bug":                                true,
+		"Kind/Bug AND label is KIND/BUG":          true,
+		"support":                                 false,
+		"kind/bug AND NOT support":                true,
+		"kind/bug and not component/zeebe":        false,
+		"component/gateway OR component/zeebe":    true,
+		"support OR kind/feature":                 false,
+		"NOT NOT kind/bug":                        true,
+		"support OR kind/bug AND component/zeebe": true,
+		"(support OR kind/bug) AND kind/feature":  false,
+		`title matches "^\[Security\]"`:           true,
+		`title matches ^Fix`:                      false,
+		`title is "[security] update dependency"`: true,
+		"state is closed AND reason is completed": true,
+		"reason is not_planned":                   false,
+		"author is Octocat":                       true,
+		"type is bug":                             true,
+		"milestone matches ^8\\.6":                true,
+		"label is KIND/BUG":                       true,
+		"label matches ^component/":               true,
+		`"kind/bug"`:                              true,
+		`"title"`:                                 false,
+		"title":                                   false,
+	}
+	for source, expected := range tests {
+		t.Run(source, func(t *testing.T) {
+			rule, err := ParseRule(source)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, rule.Matches(issue))
+			assert.Equal(t, source, rule.String())
+		})
+	}
+}
+
+func TestParseRule_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                       "empty expression",
+		"kind/bug AND":           "unexpected end of expression",
+		"kind/bug support":       "unexpected 'support'",
+		"(kind/bug OR support":   "missing closing parenthesis",
+		"kind/bug)":              "unexpected ')'",
+		"OR kind/bug":            "unexpected 'OR'",
+		`title is "unterminated`: "unterminated quoted value",
+		"title matches":          "missing value after 'title' 'matches'",
+		`title matches "("`:      "invalid regular expression",
+		"title matches [":        "invalid regular expression",
+	}
+	for source, expected := range tests {
+		t.Run(source, func(t *testing.T) {
+			_, err := ParseRule(source)
+
+			assert.ErrorContains(t, err, expected)
+		})
+	}
+}
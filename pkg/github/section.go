@@ -1,7 +1,6 @@
 package github
 
 import (
-	"bytes"
 	"fmt"
 )
 
@@ -15,44 +14,56 @@ const (
 )
 
 type Section struct {
+	configs  []SectionConfig
+	matchers []matcher
+	policy   Policy
+	fallback string
 	sections map[string][]*Issue
 }
 
+// NewSection groups issues by the default component sections.
 func NewSection() *Section {
-	return &Section{
-		sections: make(map[string][]*Issue),
-	}
+	// the default sections select issues by labels only and cannot fail
+	section, _ := newSection(defaultSections, AllMatch, miscSection)
+	return section
 }
 
-func (s *Section) AddIssue(issue *Issue) *Section {
-	hasSection := false
-	if issue.HasBrokerLabel() {
-		s.addIssueToSection(brokerSection, issue)
-		hasSection = true
-	}
-
-	if issue.HasGatewayLabel() {
-		s.addIssueToSection(gatewaySection, issue)
-		hasSection = true
+func newSection(configs []SectionConfig, policy Policy, fallback string) (*Section, error) {
+	section := &Section{
+		configs:  configs,
+		policy:   policy,
+		fallback: fallback,
+		sections: make(map[string][]*Issue),
 	}
 
-	if issue.HasJavaClientLabel() {
-		s.addIssueToSection(javaClientSection, issue)
-		hasSection = true
+	for _, config := range configs {
+		m, err := newMatcher(config.Labels, config.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("section %q: %w", config.Title, err)
+		}
+		section.matchers = append(section.matchers, m)
 	}
 
-	if issue.HasGoClientLabel() {
-		s.addIssueToSection(goClientSection, issue)
-		hasSection = true
-	}
+	return section, nil
+}
 
-	if issue.HasZbctlLabel() {
-		s.addIssueToSection(zbctlSection, issue)
-		hasSection = true
+// AddIssue adds the issue to every matching section, or only to the first one with the
+// FirstMatch policy, or to the fallback section if no section matches. Without fallback
+// section the issue is listed before all sections.
+func (s *Section) AddIssue(issue *Issue) *Section {
+	hasSection := false
+	for i, config := range s.configs {
+		if hasSection && s.policy == FirstMatch {
+			break
+		}
+		if s.matchers[i].matches(issue) {
+			s.addIssueToSection(config.Title, issue)
+			hasSection = true
+		}
 	}
 
 	if !hasSection {
-		s.addIssueToSection(miscSection, issue)
+		s.addIssueToSection(s.fallback, issue)
 	}
 	return s
 }
@@ -92,29 +103,3 @@ func (s *Section) getIssues(section string) []*Issue {
 func (s *Section) IsEmpty() bool {
 	return len(s.sections) == 0
 }
-
-func (s *Section) String() string {
-	var b bytes.Buffer
-
-	b.WriteString(sectionToString(brokerSection, s.GetBrokerIssues()))
-	b.WriteString(sectionToString(gatewaySection, s.GetGatewayIssues()))
-	b.WriteString(sectionToString(javaClientSection, s.GetJavaClientIssues()))
-	b.WriteString(sectionToString(goClientSection, s.GetGoClientIssues()))
-	b.WriteString(sectionToString(zbctlSection, s.GetZbctlIssues()))
-	b.WriteString(sectionToString(miscSection, s.GetMiscIssues()))
-
-	return b.String()
-}
-
-func sectionToString(title string, issues []*Issue) string {
-	var b bytes.Buffer
-
-	if issues != nil {
-		b.WriteString(fmt.Sprintf("### %s\n", title))
-		for _, issue := range issues {
-			b.WriteString(fmt.Sprintf("* %s\n", issue.String()))
-		}
-	}
-
-	return b.String()
-}
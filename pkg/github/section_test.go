@@ -122,31 +122,31 @@ func TestSection_GetMiscIssues(t *testing.T) {
 	}
 }
 
-func TestSection_String(t *testing.T) {
+func TestSection_Render(t *testing.T) {
 	tests := map[string]struct {
 		section  *Section
 		expected string
 	}{
-		"Empty section": {section: NewSection(), expected: ""},
+		"Empty section": {section: NewSection(), expected: "# Test\n"},
 		"Broker section": {
 			section:  NewSection().AddIssue(createIssueWithLabel(brokerLabel)),
-			expected: createSectionString(brokerSection, 1)},
+			expected: chapterHeadings + createSectionString(brokerSection, 1)},
 		"Java Client Section": {
 			section:  NewSection().AddIssue(createIssueWithLabel(javaClientLabel)),
-			expected: createSectionString(javaClientSection, 1)},
+			expected: chapterHeadings + createSectionString(javaClientSection, 1)},
 		"Go Client Section": {
 			section:  NewSection().AddIssue(createIssueWithLabel(goClientLabel)),
-			expected: createSectionString(goClientSection, 1)},
+			expected: chapterHeadings + createSectionString(goClientSection, 1)},
 		"Misc Section": {
 			section:  NewSection().AddIssue(createIssueWithLabel(bugLabel)),
-			expected: createSectionString(miscSection, 1)},
+			expected: chapterHeadings + createSectionString(miscSection, 1)},
 		"All Sections": {
 			section: NewSection().
 				AddIssue(createIssueWithLabel(goClientLabel)).
 				AddIssue(createIssueWithLabel(bugLabel)).
 				AddIssue(createIssueWithLabel(javaClientLabel)).
 				AddIssue(createIssueWithLabel(brokerLabel)),
-			expected: createSectionString(brokerSection, 1) +
+			expected: chapterHeadings + createSectionString(brokerSection, 1) +
 				createSectionString(javaClientSection, 1) +
 				createSectionString(goClientSection, 1) +
 				createSectionString(miscSection, 1)},
@@ -157,18 +157,42 @@ func TestSection_String(t *testing.T) {
 				AddIssue(createIssueWithLabel(javaClientLabel, goClientLabel, brokerLabel)).
 				AddIssue(createIssueWithLabel(docsLabel)).
 				AddIssue(createIssueWithLabel(brokerLabel)),
-			expected: createSectionString(brokerSection, 3) +
+			expected: chapterHeadings + createSectionString(brokerSection, 3) +
 				createSectionString(javaClientSection, 1) +
 				createSectionString(goClientSection, 2) +
 				createSectionString(miscSection, 2)},
+		"Without fallback section": {
+			section: withoutFallback(t).
+				AddIssue(createIssueWithLabel(brokerLabel)).
+				AddIssue(createIssueWithLabel(bugLabel)),
+			expected: chapterHeadings + "* test ([#123](test))\n" + createSectionString(brokerSection, 1)},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			assert.Equal(t, tc.expected, tc.section.String())
+			assert.Equal(t, tc.expected, renderSection(tc.section))
 		})
 	}
 }
 
+// chapterHeadings are rendered by renderSection before the sections.
+const chapterHeadings = "# Test\n## Chapter\n"
+
+// renderSection renders the section with the default template as the only chapter of a
+// changelog.
+func renderSection(section *Section) string {
+	changelog := &Changelog{title: "Test", chapters: []*chapter{{config: ChapterConfig{Title: "Chapter"}, section: section}}}
+	return changelog.String()
+}
+
+func withoutFallback(t *testing.T) *Section {
+	t.Helper()
+	section, err := newSection(defaultSections, AllMatch, "")
+	if err != nil {
+		t.Fatalf("create section: %v", err)
+	}
+	return section
+}
+
 func createIssueWithLabel(labels ...string) *Issue {
 	return createIssue("test", 123, "test", false, labels...)
 }
@@ -0,0 +1,160 @@
+package github
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed changelog.tmpl
+var defaultTemplate string
+
+// SchemaVersion is the version of the JSON changelog schema. It is incremented on changes
+// which are not backwards compatible, i.e. when fields are removed, renamed or change
+// their meaning, but not when fields are added.
+const SchemaVersion = 1
+
+// ChangelogData is the data model changelog templates are executed with and the JSON
+// changelog is serialized from. It only contains chapters and sections with at least one
+// issue, in the order of the categories.
+type ChangelogData struct {
+	// Title is the label the changelog is generated for.
+	Title    string        `json:"title"`
+	Chapters []ChapterData `json:"chapters"`
+}
+
+type ChapterData struct {
+	Title string `json:"title"`
+	// Issues are the issues of chapters without sections, or the issues matching no section
+	// of a chapter without fallback section.
+	Issues   []IssueData   `json:"issues"`
+	Sections []SectionData `json:"sections"`
+}
+
+type SectionData struct {
+	Title  string      `json:"title"`
+	Issues []IssueData `json:"issues"`
+}
+
+type IssueData struct {
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	State       string   `json:"state"`
+	Author      string   `json:"author"`
+	Labels      []string `json:"labels"`
+	PullRequest bool     `json:"pullRequest"`
+	// LinkedPullRequests are the pull requests which close the issue.
+	LinkedPullRequests []LinkedPullRequest `json:"linkedPullRequests"`
+}
+
+type jsonChangelog struct {
+	SchemaVersion int `json:"schemaVersion"`
+	ChangelogData
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// DefaultTemplate returns the template which renders the markdown changelog.
+func DefaultTemplate() *template.Template {
+	return template.Must(template.New("changelog").Funcs(templateFuncs).Parse(defaultTemplate))
+}
+
+// ParseTemplate reads a changelog template from a file. Besides the built-in functions of
+// text/template, templates can use join to concatenate strings, e.g. {{ join .Labels ", " }}.
+func ParseTemplate(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read changelog template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse changelog template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render executes the template with the data of the changelog.
+func (c *Changelog) Render(w io.Writer, tmpl *template.Template) error {
+	if err := tmpl.Execute(w, c.Data()); err != nil {
+		return fmt.Errorf("unable to render changelog template: %w", err)
+	}
+	return nil
+}
+
+// JSON returns the changelog as indented JSON document with the SchemaVersion.
+func (c *Changelog) JSON() ([]byte, error) {
+	return json.MarshalIndent(jsonChangelog{SchemaVersion: SchemaVersion, ChangelogData: c.Data()}, "", "  ")
+}
+
+// Data returns the chapters, sections and issues of the changelog. Lists are never nil,
+// so that they are serialized as empty JSON arrays.
+func (c *Changelog) Data() ChangelogData {
+	data := ChangelogData{Title: c.title, Chapters: []ChapterData{}}
+
+	for _, chapter := range c.chapters {
+		chapterData := ChapterData{Title: chapter.config.Title, Issues: []IssueData{}, Sections: []SectionData{}}
+		if chapter.section != nil {
+			chapterData.Issues, chapterData.Sections = chapter.section.data()
+		} else {
+			chapterData.Issues = issueData(chapter.issues)
+		}
+
+		if len(chapterData.Issues) > 0 || len(chapterData.Sections) > 0 {
+			data.Chapters = append(data.Chapters, chapterData)
+		}
+	}
+
+	return data
+}
+
+// data returns the issues without section, if there is no fallback section, and the
+// non-empty sections.
+func (s *Section) data() ([]IssueData, []SectionData) {
+	issues := []IssueData{}
+	if s.fallback == "" {
+		issues = issueData(s.getIssues(""))
+	}
+
+	titles := make([]string, 0, len(s.configs)+1)
+	for _, config := range s.configs {
+		titles = append(titles, config.Title)
+	}
+	if s.fallback != "" {
+		titles = append(titles, s.fallback)
+	}
+
+	sections := []SectionData{}
+	for _, title := range titles {
+		if sectionIssues := s.getIssues(title); len(sectionIssues) > 0 {
+			sections = append(sections, SectionData{Title: title, Issues: issueData(sectionIssues)})
+		}
+	}
+
+	return issues, sections
+}
+
+func issueData(issues []*Issue) []IssueData {
+	data := make([]IssueData, 0, len(issues))
+	for _, issue := range issues {
+		data = append(data, IssueData{
+			Number:             issue.Number(),
+			Title:              issue.Title(),
+			URL:                issue.URL(),
+			State:              issue.State(),
+			Author:             issue.Author(),
+			Labels:             issue.Labels(),
+			PullRequest:        issue.IsPullRequest(),
+			LinkedPullRequests: append([]LinkedPullRequest{}, issue.LinkedPullRequests()...),
+		})
+	}
+	return data
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelog_Render(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changelog.tmpl")
+	content := `{{ range .Chapters }}{{ .Title }}:{{ range .Sections }} {{ .Title }}{{ range .Issues }} #{{ .Number }} [{{ join .Labels "," }}]` +
+		`{{ range .LinkedPullRequests }} via #{{ .Number }}{{ end }}{{ end }}{{ end }}
+{{ end }}`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	issue := createIssue("Feature", 1, "u1", false, featureLabel, brokerLabel)
+	issue.linkedPullRequests = []LinkedPullRequest{{Number: 10, Title: "feat: feature", URL: "u10"}}
+	changelog := NewChangelog("Test").
+		AddIssue(issue).
+		AddIssue(createIssue("Bug", 2, "u2", false, bugLabel))
+
+	tmpl, err := ParseTemplate(path)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	assert.NoError(t, changelog.Render(&b, tmpl))
+	assert.Equal(t, "Enhancements: Broker #1 [kind/feature,scope/broker] via #10\nBug Fixes: Misc #2 [kind/bug]\n", b.String())
+}
+
+func TestChangelog_Data(t *testing.T) {
+	categories := Categories{Chapters: []ChapterConfig{
+		{Title: "Empty", Labels: []string{"kind/epic"}},
+		{Title: "Bugs", Labels: []string{bugLabel}, Sections: []SectionConfig{{Title: "Broker", Labels: []string{brokerLabel}}}},
+	}}
+	changelog, err := NewChangelogWithCategories("8.6.0", categories)
+	assert.NoError(t, err)
+	changelog.AddIssue(createIssue("Bug", 2, "u2", false, bugLabel))
+
+	assert.Equal(t, ChangelogData{
+		Title: "8.6.0",
+		Chapters: []ChapterData{{
+			Title:    "Bugs",
+			Issues:   []IssueData{{Number: 2, Title: "Bug", URL: "u2", Labels: []string{bugLabel}, LinkedPullRequests: []LinkedPullRequest{}}},
+			Sections: []SectionData{},
+		}},
+	}, changelog.Data())
+}
+
+func TestChangelog_JSON(t *testing.T) {
+	issue := createIssue("Feature", 1, "u1", false, featureLabel, brokerLabel)
+	issue.linkedPullRequests = []LinkedPullRequest{{Number: 10, Title: "feat: feature", URL: "u10"}}
+	changelog := NewChangelog("8.6.0").
+		AddIssue(issue).
+		AddIssue(createIssue("Bump", 2, "u2", true))
+
+	output, err := changelog.JSON()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"schemaVersion": 1,
+		"title": "8.6.0",
+		"chapters": [
+			{"title": "Enhancements", "issues": [], "sections": [
+				{"title": "Broker", "issues": [{
+					"number": 1, "title": "Feature", "url": "u1", "state": "", "author": "",
+					"labels": ["kind/feature", "scope/broker"], "pullRequest": false,
+					"linkedPullRequests": [{"number": 10, "title": "feat: feature", "url": "u10"}]
+				}]}
+			]},
+			{"title": "Merged Pull Requests", "sections": [], "issues": [{
+				"number": 2, "title": "Bump", "url": "u2", "state": "", "author": "",
+				"labels": [], "pullRequest": true, "linkedPullRequests": []
+			}]}
+		]
+	}`, string(output))
+}
+
+func TestChangelog_JSONEmpty(t *testing.T) {
+	output, err := NewChangelog("8.6.0").JSON()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"schemaVersion": 1, "title": "8.6.0", "chapters": []}`, string(output))
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changelog.tmpl")
+	assert.NoError(t, os.WriteFile(path, []byte("{{ range .Chapters }}"), 0o644))
+
+	_, err := ParseTemplate(path)
+
+	assert.ErrorContains(t, err, "unable to parse changelog template")
+}
+
+func TestChangelog_RenderFailure(t *testing.T) {
+	tmpl, err := DefaultTemplate().Parse("{{ .Unknown }}")
+	assert.NoError(t, err)
+
+	err = NewChangelog("Test").Render(&bytes.Buffer{}, tmpl)
+
+	assert.ErrorContains(t, err, "unable to render changelog template")
+}
@@ -0,0 +1,174 @@
+package gitlog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+
+	// logFormat renders every commit as a record of unit separated fields, so that
+	// subjects and bodies can contain arbitrary text without breaking the parser.
+	logFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%cI%x1f%s%x1f%b%x1f%(trailers:only,unfold)%x1e"
+	logFields = 8
+
+	// cherryPickNote is appended to the message of commits cherry-picked with "git cherry-pick -x".
+	cherryPickNote = "(cherry picked from commit "
+)
+
+var (
+	// mergeSubjectRegex matches the subject of merge commits created by GitHub, which names
+	// the pull request and its head branch, e.g. "Merge pull request #12 from camunda/fix".
+	mergeSubjectRegex = regexp.MustCompile(`^Merge pull request #(\d+) from (\S+)`)
+	// squashSubjectRegex matches the pull request number GitHub appends to the subject of
+	// squash merged pull requests, e.g. "fix: handle timeouts (#1234)".
+	squashSubjectRegex = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+)
+
+type Author struct {
+	Name  string
+	Email string
+}
+
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+type Commit struct {
+	SHA           string
+	Parents       []string
+	Author        Author
+	CommitterDate time.Time
+	Subject       string
+	Body          string
+	Trailers      []Trailer
+}
+
+// Message returns the full commit message, i.e. the subject followed by the body.
+func (c *Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+func (c *Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// PullRequest returns the number of the pull request which was merged by this commit,
+// based on the subject GitHub generates for merge and squash merge commits.
+func (c *Commit) PullRequest() (int, bool) {
+	regex := mergeSubjectRegex
+	if !c.IsMerge() {
+		regex = squashSubjectRegex
+	}
+
+	match := regex.FindStringSubmatch(c.Subject)
+	if match == nil {
+		return 0, false
+	}
+
+	pullRequest, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return pullRequest, true
+}
+
+// MergedBranch returns the head branch of the pull request merged by this commit in
+// owner/branch notation, based on the subject GitHub generates for merge commits.
+func (c *Commit) MergedBranch() (string, bool) {
+	if !c.IsMerge() {
+		return "", false
+	}
+
+	match := mergeSubjectRegex.FindStringSubmatch(c.Subject)
+	if match == nil {
+		return "", false
+	}
+	return match[2], true
+}
+
+// IsBackport reports whether the commit backports changes to a maintenance branch, i.e.
+// it merges a backport branch, its subject starts with "[Backport" or its message
+// contains the note "git cherry-pick -x" adds to cherry-picked commits.
+func (c *Commit) IsBackport() bool {
+	if branch, ok := c.MergedBranch(); ok {
+		_, name, _ := strings.Cut(branch, "/")
+		if strings.HasPrefix(strings.ToLower(name), "backport") {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(strings.ToLower(c.Subject), "[backport") ||
+		strings.Contains(c.Body, cherryPickNote)
+}
+
+func (c *Commit) ShortSHA() string {
+	if len(c.SHA) > 10 {
+		return c.SHA[:10]
+	}
+	return c.SHA
+}
+
+func parseCommits(output string) ([]Commit, error) {
+	var commits []Commit
+
+	for _, record := range strings.Split(output, recordSeparator) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		commit, err := parseCommit(record)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+func parseCommit(record string) (Commit, error) {
+	fields := strings.Split(record, fieldSeparator)
+	if len(fields) != logFields {
+		return Commit{}, fmt.Errorf("unable to parse git log record with %d fields, expected %d: %q", len(fields), logFields, record)
+	}
+
+	committerDate, err := time.Parse(time.RFC3339, fields[4])
+	if err != nil {
+		return Commit{}, fmt.Errorf("unable to parse committer date of commit %s: %w", fields[0], err)
+	}
+
+	return Commit{
+		SHA:           fields[0],
+		Parents:       strings.Fields(fields[1]),
+		Author:        Author{Name: fields[2], Email: fields[3]},
+		CommitterDate: committerDate,
+		Subject:       fields[5],
+		Body:          strings.TrimSpace(fields[6]),
+		Trailers:      parseTrailers(fields[7]),
+	}, nil
+}
+
+func parseTrailers(block string) []Trailer {
+	var trailers []Trailer
+
+	for _, line := range strings.Split(block, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+
+	return trailers
+}
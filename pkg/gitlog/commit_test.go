@@ -0,0 +1,114 @@
+package gitlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommits(t *testing.T) {
+	output := "abc\x1fp1 p2\x1fJane Doe\x1fjane@example.com\x1f2024-05-01T10:00:00+02:00\x1fMerge pull request #12\x1fcloses #1\n\nSigned-off-by: Jane\n\x1fSigned-off-by: Jane\n\x1e\n" +
+		"def\x1fp3\x1fJohn Doe\x1fjohn@example.com\x1f2024-05-02T10:00:00Z\x1ffix: something (#13)\x1f\x1f\x1e\n"
+
+	commits, err := parseCommits(output)
+
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+
+	assert.Equal(t, "abc", commits[0].SHA)
+	assert.Equal(t, []string{"p1", "p2"}, commits[0].Parents)
+	assert.True(t, commits[0].IsMerge())
+	assert.Equal(t, Author{Name: "Jane Doe", Email: "jane@example.com"}, commits[0].Author)
+	assert.True(t, commits[0].CommitterDate.Equal(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Merge pull request #12", commits[0].Subject)
+	assert.Equal(t, "closes #1\n\nSigned-off-by: Jane", commits[0].Body)
+	assert.Equal(t, []Trailer{{Key: "Signed-off-by", Value: "Jane"}}, commits[0].Trailers)
+	assert.Equal(t, "Merge pull request #12\n\ncloses #1\n\nSigned-off-by: Jane", commits[0].Message())
+
+	assert.Equal(t, "def", commits[1].SHA)
+	assert.False(t, commits[1].IsMerge())
+	assert.Equal(t, "fix: something (#13)", commits[1].Message())
+	assert.Nil(t, commits[1].Trailers)
+}
+
+func TestParseCommitsRejectsMalformedRecord(t *testing.T) {
+	_, err := parseCommits("abc\x1fp1\x1e")
+
+	assert.Error(t, err)
+}
+
+func TestGetHistoryReturnsMergeCommits(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+	runGit(t, repoDir, "merge", "--no-ff", "branch-a", "-m", "Merge pull request #7 from camunda/branch-a", "-m", "closes #42\n\nCo-authored-by: Jane <jane@example.com>")
+
+	commits := getHistory(t, repoDir, base, "branch-b", HistoryOptions{Mode: MergesMode})
+
+	assert.Len(t, commits, 1)
+	commit := commits[0]
+	assert.Len(t, commit.Parents, 2)
+	assert.Equal(t, "zcl-tests", commit.Author.Name)
+	assert.Equal(t, "Merge pull request #7 from camunda/branch-a", commit.Subject)
+	assert.Contains(t, commit.Body, "closes #42")
+	assert.Equal(t, []Trailer{{Key: "Co-authored-by", Value: "Jane <jane@example.com>"}}, commit.Trailers)
+	assert.Equal(t, []int{7, 42}, referenceIds(newCamundaExtractor(t).ExtractIssueIds(commits)))
+}
+
+func TestCommitPullRequest(t *testing.T) {
+	tests := map[string]struct {
+		commit      Commit
+		pullRequest int
+		found       bool
+	}{
+		"Merge commit":         {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge pull request #12 from camunda/branch"}, pullRequest: 12, found: true},
+		"Merge of branch":      {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge branch 'stable/8.5'"}, found: false},
+		"Squash commit":        {commit: Commit{Parents: []string{"a"}, Subject: "fix: handle timeouts (#13)"}, pullRequest: 13, found: true},
+		"Squash subject merge": {commit: Commit{Parents: []string{"a", "b"}, Subject: "fix: handle timeouts (#13)"}, found: false},
+		"Merge subject squash": {commit: Commit{Parents: []string{"a"}, Subject: "Merge pull request #12 from camunda/branch"}, found: false},
+		"Plain commit":         {commit: Commit{Parents: []string{"a"}, Subject: "fix: handle timeouts"}, found: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pullRequest, found := tc.commit.PullRequest()
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.pullRequest, pullRequest)
+		})
+	}
+}
+
+func TestCommitMergedBranch(t *testing.T) {
+	tests := map[string]struct {
+		commit Commit
+		branch string
+		found  bool
+	}{
+		"Merge commit":    {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge pull request #12 from camunda/fix-timeouts"}, branch: "camunda/fix-timeouts", found: true},
+		"Merge of branch": {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge branch 'stable/8.5'"}, found: false},
+		"Squash commit":   {commit: Commit{Parents: []string{"a"}, Subject: "Merge pull request #12 from camunda/fix-timeouts"}, found: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			branch, found := tc.commit.MergedBranch()
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.branch, branch)
+		})
+	}
+}
+
+func TestCommitIsBackport(t *testing.T) {
+	tests := map[string]struct {
+		commit   Commit
+		backport bool
+	}{
+		"Backport branch":  {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge pull request #12 from camunda/backport-11-to-stable/8.5"}, backport: true},
+		"Backport subject": {commit: Commit{Parents: []string{"a"}, Subject: "[Backport stable/8.5] fix: handle timeouts (#13)"}, backport: true},
+		"Cherry-picked":    {commit: Commit{Parents: []string{"a"}, Subject: "fix: handle timeouts", Body: "(cherry picked from commit 0123456789abcdef)"}, backport: true},
+		"Feature branch":   {commit: Commit{Parents: []string{"a", "b"}, Subject: "Merge pull request #12 from camunda/fix-backport-detection"}, backport: false},
+		"Plain commit":     {commit: Commit{Parents: []string{"a"}, Subject: "fix: handle timeouts (#13)"}, backport: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.backport, tc.commit.IsBackport())
+		})
+	}
+}
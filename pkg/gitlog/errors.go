@@ -0,0 +1,7 @@
+package gitlog
+
+import "errors"
+
+// ErrInvalidRange is returned if the revisions of a git range cannot be resolved, or no
+// previous release can be detected for a revision.
+var ErrInvalidRange = errors.New("invalid git range")
@@ -0,0 +1,57 @@
+package gitlog
+
+import "strings"
+
+// Trace is a commit which references an issue, together with the part of the commit
+// message or pull request which matched.
+type Trace struct {
+	Commit *Commit
+	// Line is the keyword line of the message which references the issue, or the subject
+	// of a squash merged pull request which is the issue itself.
+	Line string
+	// Source tells where the reference was found.
+	Source ReferenceSource
+	// PullRequest is the number of the pull request the reference was found in or which
+	// closes the issue, zero for references in commit messages.
+	PullRequest int
+}
+
+// Trace returns every reference of the issue in the commits, a commit which references
+// the issue on several lines is returned once per line.
+func (e *Extractor) Trace(commits []Commit, issue IssueReference) []Trace {
+	var traces []Trace
+
+	for i := range commits {
+		commit := &commits[i]
+
+		if !commit.IsMerge() {
+			if pullRequest, ok := commit.PullRequest(); ok {
+				reference := IssueReference{Owner: e.owner, Repo: e.repo, ID: pullRequest}
+				if reference.Key() == issue.Key() {
+					traces = append(traces, Trace{Commit: commit, Line: commit.Subject, Source: CommitSource})
+				}
+			}
+		}
+
+		for _, line := range e.TraceText(commit.Message(), issue) {
+			traces = append(traces, Trace{Commit: commit, Line: line, Source: CommitSource})
+		}
+	}
+
+	return traces
+}
+
+// TraceText returns the keyword lines of an arbitrary text which reference the issue,
+// e.g. of the body of a pull request.
+func (e *Extractor) TraceText(text string, issue IssueReference) []string {
+	var lines []string
+	for _, line := range e.lineRegex.FindAllString(text, -1) {
+		for _, reference := range e.referencesInLine(line) {
+			if reference.Key() == issue.Key() {
+				lines = append(lines, strings.TrimSpace(line))
+				break
+			}
+		}
+	}
+	return lines
+}
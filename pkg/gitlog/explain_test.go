@@ -0,0 +1,35 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	extractor := newCamundaExtractor(t)
+	commits := []Commit{
+		{SHA: "a", Parents: []string{"p1", "p2"}, Subject: "Merge pull request #20 from camunda/fix-timeouts", Body: "fix: handle timeouts\n\ncloses #21345"},
+		{SHA: "b", Parents: []string{"p3"}, Subject: "fix: handle timeouts (#21345)"},
+		{SHA: "c", Parents: []string{"p4", "p5"}, Subject: "Merge pull request #30 from camunda/backport-20-to-stable/8.5", Body: "related to camunda/camunda#21345 and #1\n  relates to https://github.com/camunda/camunda/issues/21345"},
+		{SHA: "d", Parents: []string{"p6", "p7"}, Subject: "Merge pull request #40 from camunda/other", Body: "closes camunda/zeebe#21345\nmentions #21345"},
+	}
+
+	traces := extractor.Trace(commits, IssueReference{Owner: "camunda", Repo: "camunda", ID: 21345})
+
+	assert.Equal(t, []Trace{
+		{Commit: &commits[0], Line: "closes #21345", Source: CommitSource},
+		{Commit: &commits[1], Line: "fix: handle timeouts (#21345)", Source: CommitSource},
+		{Commit: &commits[2], Line: "related to camunda/camunda#21345 and #1", Source: CommitSource},
+		{Commit: &commits[2], Line: "relates to https://github.com/camunda/camunda/issues/21345", Source: CommitSource},
+	}, traces)
+}
+
+func TestTraceText(t *testing.T) {
+	extractor := newCamundaExtractor(t)
+	body := "Handles timeouts of the gateway.\n\ncloses #21345\nrelated to #1\nsee #21345\n- Fixes Camunda/Camunda#21345"
+
+	lines := extractor.TraceText(body, IssueReference{Owner: "camunda", Repo: "camunda", ID: 21345})
+
+	assert.Equal(t, []string{"closes #21345", "- Fixes Camunda/Camunda#21345"}, lines)
+}
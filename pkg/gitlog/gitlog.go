@@ -4,20 +4,67 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
-	"regexp"
-	"strconv"
 	"strings"
 )
 
-var (
-	lineRegex = regexp.MustCompile(`(?im)^\s*(closes?|related|relates|merges?|back\s?ports?|resolved|resolves)\s+.*$`)
-	idRegex   = regexp.MustCompile(`(\s#|(https?\:\/\/(www\.)?github\.com\/)?camunda\/(camunda|zeebe)(\/|#))(\d+)`)
+type HistoryMode string
+
+const (
+	// MergesMode only considers merge commits, i.e. pull requests merged with a merge commit.
+	MergesMode HistoryMode = "merges"
+	// FirstParentMode follows the first parent of merges, which yields the squash and
+	// rebase merged commits as well as the merge commits of the target branch.
+	FirstParentMode HistoryMode = "first-parent"
+	// AllMode considers every commit in the range.
+	AllMode HistoryMode = "all"
 )
 
-func GetHistory(path, start, end string) string {
+var HistoryModes = []HistoryMode{MergesMode, FirstParentMode, AllMode}
+
+func ParseHistoryMode(mode string) (HistoryMode, error) {
+	for _, historyMode := range HistoryModes {
+		if string(historyMode) == mode {
+			return historyMode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown history mode %q, expected one of %v", mode, HistoryModes)
+}
+
+type HistoryOptions struct {
+	Mode HistoryMode
+	// IncludePaths are glob patterns, e.g. "zeebe/**". If set, only commits which change
+	// at least one matching path are returned.
+	IncludePaths []string
+	// ExcludePaths are glob patterns of paths whose changes are ignored.
+	ExcludePaths []string
+}
+
+func (o HistoryOptions) pathspecs() []string {
+	var pathspecs []string
+	for _, pattern := range o.IncludePaths {
+		pathspecs = append(pathspecs, ":(glob)"+pattern)
+	}
+	for _, pattern := range o.ExcludePaths {
+		pathspecs = append(pathspecs, ":(glob,exclude)"+pattern)
+	}
+	return pathspecs
+}
+
+func (o HistoryOptions) logArgs() []string {
+	switch o.Mode {
+	case FirstParentMode:
+		return []string{"--first-parent"}
+	case AllMode:
+		return nil
+	default:
+		return []string{"--merges"}
+	}
+}
+
+func GetHistory(path, start, end string, options HistoryOptions) ([]Commit, error) {
 	err := validateAncestor(path, start, end)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	logRange := fmt.Sprintf("%s..%s", start, end)
@@ -26,51 +73,109 @@ func GetHistory(path, start, end string) string {
 	// Note: We removed the --since filter because it was incorrectly filtering out backported fixes
 	// that were committed before the start tag was released. The git revision range (start..end)
 	// already correctly determines which commits are new between revisions.
-	command := exec.Command("git", "-C", path, "log", logRange, "--merges", "--")
+	args := append([]string{"-C", path, "log", logRange}, options.logArgs()...)
+	command := exec.Command("git", append(args, "--format="+logFormat, "--")...)
 	log.Println(command)
-	out, err := command.CombinedOutput()
-	result := string(out)
+	out, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read git history of %s: %s (%w)", logRange, commandOutput(err), err)
+	}
 
+	commits, err := parseCommits(string(out))
 	if err != nil {
-		log.Fatal(result, err)
+		return nil, err
 	}
 
-	return result
+	if pathspecs := options.pathspecs(); len(pathspecs) > 0 {
+		log.Println("Filtering", len(commits), "commits by paths", strings.Join(pathspecs, " "))
+		commits, err = filterByPaths(path, commits, pathspecs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commits, nil
+}
+
+// commandOutput returns the error output of a failed command.
+func commandOutput(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return ""
+}
+
+// filterByPaths returns the commits which change any path matching the pathspecs. Merge
+// commits are compared to their first parent, i.e. the changes of the merged branch.
+func filterByPaths(path string, commits []Commit, pathspecs []string) ([]Commit, error) {
+	var filtered []Commit
+
+	for _, commit := range commits {
+		args := []string{"-C", path, "diff-tree", "-r", "--name-only", "--no-commit-id"}
+		if len(commit.Parents) == 0 {
+			args = append(args, "--root", commit.SHA)
+		} else {
+			args = append(args, commit.Parents[0], commit.SHA)
+		}
+
+		out, err := exec.Command("git", append(append(args, "--"), pathspecs...)...).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("unable to list changed paths of commit %s: %s (%w)", commit.SHA, strings.TrimSpace(string(out)), err)
+		}
+
+		if strings.TrimSpace(string(out)) != "" {
+			filtered = append(filtered, commit)
+		}
+	}
+
+	return filtered, nil
 }
 
 func validateAncestor(path, start, end string) error {
+	ancestor, err := isAncestor(path, start, end)
+	if err != nil {
+		return err
+	}
+
+	if !ancestor {
+		log.Printf("warning: git range %s..%s has start that is not an ancestor of end; continuing anyway", start, end)
+	}
+	return nil
+}
+
+func isAncestor(path, start, end string) (bool, error) {
 	command := exec.Command("git", "-C", path, "merge-base", "--is-ancestor", start, end)
 	log.Println(command)
 	out, err := command.CombinedOutput()
 	if err == nil {
-		return nil
+		return true, nil
 	}
 
 	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-		log.Printf("warning: git range %s..%s has start that is not an ancestor of end; continuing anyway", start, end)
-		return nil
+		return false, nil
 	}
 
-	return fmt.Errorf("unable to validate git range %s..%s: %s (%w)", start, end, strings.TrimSpace(string(out)), err)
+	return false, fmt.Errorf("%w: unable to validate git range %s..%s: %s (%w)", ErrInvalidRange, start, end, strings.TrimSpace(string(out)), err)
 }
 
-func ExtractIssueIds(message string) []int {
-	seen := map[int]bool{}
-	var issueIds []int
-
-	for _, line := range lineRegex.FindAllString(message, -1) {
-		for _, match := range idRegex.FindAllStringSubmatch(line, -1) {
-			issueId, err := strconv.Atoi(match[6])
-			if err != nil {
-				log.Fatalln("Cannot convert issue id", match[6], err)
-			}
-
-			if !seen[issueId] {
-				seen[issueId] = true
-				issueIds = append(issueIds, issueId)
-			}
-		}
+// Toplevel returns the root directory of the working tree which contains the path, or
+// false if the path is not inside a git working tree.
+func Toplevel(path string) (string, bool) {
+	out, err := exec.Command("git", "-C", path, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", false
 	}
+	return strings.TrimSpace(string(out)), true
+}
 
-	return issueIds
+// ResolveCommit returns the SHA of the commit the revision points to, e.g. to detect that
+// a tag was moved.
+func ResolveCommit(path, revision string) (string, error) {
+	command := exec.Command("git", "-C", path, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	log.Println(command)
+	out, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to resolve revision %s: %s (%w)", ErrInvalidRange, revision, commandOutput(err), err)
+	}
+	return strings.TrimSpace(string(out)), nil
 }
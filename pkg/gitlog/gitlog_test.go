@@ -99,11 +99,11 @@ func TestGitHistory(t *testing.T) {
 		path        string
 		start       string
 		end         string
-		size        int
+		empty       bool
 		needCamunda bool
 	}{
-		"First commit in zcl":          {path: ".", start: "7b86247", end: "7ab8381", size: 0},
-		"Between tags in camunda repo": {start: "8.5.0", end: "8.6.0-alpha1", size: 2012037, needCamunda: true},
+		"First commit in zcl":          {path: ".", start: "7b86247", end: "7ab8381", empty: true},
+		"Between tags in camunda repo": {start: "8.5.0", end: "8.6.0-alpha1", needCamunda: true},
 	}
 
 	var camundaRepo string
@@ -117,8 +117,15 @@ func TestGitHistory(t *testing.T) {
 				path = camundaRepo
 			}
 
-			log := GetHistory(path, tc.start, tc.end)
-			assert.Equal(t, tc.size, len(log))
+			commits := getHistory(t, path, tc.start, tc.end, HistoryOptions{Mode: MergesMode})
+			if tc.empty {
+				assert.Empty(t, commits)
+				return
+			}
+			assert.NotEmpty(t, commits)
+			for _, commit := range commits {
+				assert.True(t, commit.IsMerge(), "expected merge commit %s", commit.SHA)
+			}
 		})
 	}
 }
@@ -136,9 +143,9 @@ func TestRegressionBackportIssue40036IncludedInReleaseRanges(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			camundaRepo := prepareCamundaRepo(t, tc.from, tc.to)
-			history := GetHistory(camundaRepo, tc.from, tc.to)
-			assert.Contains(t, history, tc.expectedCommit)
-			issueIDs := ExtractIssueIds(history)
+			history := getHistory(t, camundaRepo, tc.from, tc.to, HistoryOptions{Mode: MergesMode})
+			assert.Contains(t, commitSHAs(history), tc.expectedCommit)
+			issueIDs := referenceIds(newCamundaExtractor(t).ExtractIssueIds(history))
 			assert.Contains(t, issueIDs, 40036)
 		})
 	}
@@ -174,19 +181,48 @@ func TestExtractIssueIds(t *testing.T) {
 		"Short reference":               {message: "closes camunda/camunda#1234", issueIds: []int{1234}},
 		"Short old reference":           {message: "closes camunda/zeebe#1234", issueIds: []int{1234}},
 		"Wrong repo reference URL":      {message: "closes https://www.github.com/camunda/operate/1234", issueIds: nil},
-		"Wrong repo short reference":    {message: "closes camunda/operate#1234", issueIds: nil},
-		"Wrong org URL reference":       {message: "closes https://www.github.com/zeebe-io/zeebe#1234", issueIds: nil},
-		"Wrong org short reference":     {message: "closes zeebe-io/zeebe#1234", issueIds: nil},
 		"Multiple issues mixed format":  {message: "closes #1234, camunda/zeebe#5678, camunda/camunda#9 and https://www.github.com/camunda/camunda/123", issueIds: []int{1234, 5678, 9, 123}},
+		"Closed keyword":                {message: "closed #1234", issueIds: []int{1234}},
+		"Fix keyword":                   {message: "fix #1234", issueIds: []int{1234}},
+		"Fixes keyword":                 {message: "fixes #1234", issueIds: []int{1234}},
+		"Fixed keyword":                 {message: "fixed #1234", issueIds: []int{1234}},
+		"Resolve keyword":               {message: "resolve #1234", issueIds: []int{1234}},
+		"Resolves keyword":              {message: "resolves #1234", issueIds: []int{1234}},
+		"Resolved keyword":              {message: "resolved #1234", issueIds: []int{1234}},
+		"Issue URL reference":           {message: "closes https://github.com/camunda/camunda/issues/1234", issueIds: []int{1234}},
+		"Pull request URL reference":    {message: "closes https://github.com/camunda/camunda/pull/1234", issueIds: []int{1234}},
+		"Squash subject":                {message: "fix: handle timeouts (#1234)", issueIds: []int{1234}},
+		"Squash subject with body":      {message: "fix: handle timeouts (#1234)\n\n* closes #5678\n- related to #9", issueIds: []int{1234, 5678, 9}},
+		"Pull request number in text":   {message: "fix: handle timeouts (#1234) again", issueIds: nil},
 	}
+	extractor := newCamundaExtractor(t)
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			issueIds := ExtractIssueIds(tc.message)
+			issueIds := referenceIds(extractor.ExtractIssueIds([]Commit{commitWithMessage(tc.message)}))
 			assert.Equal(t, tc.issueIds, issueIds)
 		})
 	}
 }
 
+func TestExtractIssueIdsIgnoresSquashSubjectOfMerges(t *testing.T) {
+	commits := []Commit{{SHA: "a", Parents: []string{"p1", "p2"}, Subject: "Merge branch 'stable' (#1234)"}}
+
+	assert.Empty(t, newCamundaExtractor(t).ExtractIssueIds(commits))
+}
+
+func TestExtractIssueIdsKeepsCommit(t *testing.T) {
+	commits := []Commit{
+		{SHA: "a", Subject: "Merge pull request #10", Body: "closes #1"},
+		{SHA: "b", Subject: "Merge pull request #11", Body: "closes #2, #1"},
+	}
+
+	references := newCamundaExtractor(t).ExtractIssueIds(commits)
+
+	assert.Equal(t, []int{10, 1, 11, 2}, referenceIds(references))
+	assert.Equal(t, "a", references[1].Commit.SHA)
+	assert.Equal(t, "b", references[3].Commit.SHA)
+}
+
 func TestValidateAncestor(t *testing.T) {
 	repoDir, base, branchA, branchB := prepareDivergedRepo(t)
 
@@ -204,6 +240,211 @@ func TestValidateAncestor(t *testing.T) {
 func TestGetHistoryAllowsNonAncestorRanges(t *testing.T) {
 	repoDir, _, branchA, branchB := prepareDivergedRepo(t)
 
-	history := GetHistory(repoDir, branchA, branchB)
-	assert.Equal(t, "", history)
+	history := getHistory(t, repoDir, branchA, branchB, HistoryOptions{Mode: MergesMode})
+	assert.Empty(t, history)
+}
+
+func commitWithMessage(message string) Commit {
+	subject, body, _ := strings.Cut(message, "\n\n")
+	return Commit{Subject: subject, Body: body}
+}
+
+func TestExtractIssueIdsOtherRepositories(t *testing.T) {
+	tests := map[string]struct {
+		message    string
+		references []string
+	}{
+		"Other repo short reference":  {message: "closes camunda/operate#1234", references: []string{"camunda/operate#1234"}},
+		"Other org URL reference":     {message: "closes https://www.github.com/zeebe-io/zeebe#1234", references: []string{"zeebe-io/zeebe#1234"}},
+		"Other org short reference":   {message: "closes zeebe-io/zeebe#1234", references: []string{"zeebe-io/zeebe#1234"}},
+		"Other repo issue URL":        {message: "closes https://github.com/camunda/connectors/issues/412", references: []string{"camunda/connectors#412"}},
+		"Other repo pull request URL": {message: "closes https://github.com/camunda/connectors/pull/413", references: []string{"camunda/connectors#413"}},
+		"Repository with dot":         {message: "closes camunda/camunda.io#7", references: []string{"camunda/camunda.io#7"}},
+		"Mixed repositories":          {message: "closes #1, camunda/connectors#412 and camunda/camunda#2", references: []string{"camunda/camunda#1", "camunda/connectors#412", "camunda/camunda#2"}},
+		"Owner not at word start":     {message: "closes xcamunda/camunda#1", references: []string{"xcamunda/camunda#1"}},
+		"Owner after hyphen":          {message: "closes foo-camunda/camunda#1", references: []string{"foo-camunda/camunda#1"}},
+		"Reference in path":           {message: "closes docs/camunda/camunda#1", references: nil},
+	}
+	extractor := newCamundaExtractor(t)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var references []string
+			for _, reference := range extractor.ExtractIssueIds([]Commit{commitWithMessage(tc.message)}) {
+				references = append(references, reference.String())
+			}
+			assert.Equal(t, tc.references, references)
+		})
+	}
+}
+
+// referenceIds returns the plain issue ids of the given references.
+func referenceIds(references []IssueReference) []int {
+	var issueIds []int
+	for _, reference := range references {
+		issueIds = append(issueIds, reference.ID)
+	}
+	return issueIds
+}
+
+func newCamundaExtractor(t *testing.T) *Extractor {
+	t.Helper()
+	extractor, err := NewExtractor("camunda", "camunda", ReferenceRules{Repositories: []string{"camunda/camunda", "camunda/zeebe"}})
+	if err != nil {
+		t.Fatalf("create extractor: %v", err)
+	}
+	return extractor
+}
+
+func commitSHAs(commits []Commit) []string {
+	var shas []string
+	for _, commit := range commits {
+		shas = append(shas, commit.SHA)
+	}
+	return shas
+}
+
+func TestGetHistoryModes(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+	writeAndCommit(t, repoDir, "squash.txt", "fix: handle timeouts (#21)", "* closes #20")
+	runGit(t, repoDir, "merge", "--no-ff", "branch-a", "-m", "Merge pull request #22 from camunda/branch-a")
+
+	tests := map[string]struct {
+		mode     HistoryMode
+		subjects []string
+		issueIds []int
+	}{
+		"Merges": {
+			mode:     MergesMode,
+			subjects: []string{"Merge pull request #22 from camunda/branch-a"},
+			issueIds: []int{22},
+		},
+		"First parent": {
+			mode:     FirstParentMode,
+			subjects: []string{"Merge pull request #22 from camunda/branch-a", "fix: handle timeouts (#21)", "branch-b"},
+			issueIds: []int{22, 21, 20},
+		},
+		"All": {
+			mode:     AllMode,
+			subjects: []string{"Merge pull request #22 from camunda/branch-a", "fix: handle timeouts (#21)", "branch-a", "branch-b"},
+			issueIds: []int{22, 21, 20},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			commits := getHistory(t, repoDir, base, "HEAD", HistoryOptions{Mode: tc.mode})
+
+			var subjects []string
+			for _, commit := range commits {
+				subjects = append(subjects, commit.Subject)
+			}
+			assert.ElementsMatch(t, tc.subjects, subjects)
+			assert.Equal(t, tc.issueIds, referenceIds(newCamundaExtractor(t).ExtractIssueIds(commits)))
+		})
+	}
+}
+
+func TestParseHistoryMode(t *testing.T) {
+	for _, mode := range HistoryModes {
+		parsed, err := ParseHistoryMode(string(mode))
+		assert.NoError(t, err)
+		assert.Equal(t, mode, parsed)
+	}
+
+	_, err := ParseHistoryMode("squash")
+	assert.Error(t, err)
+}
+
+func writeAndCommit(t *testing.T, repoDir, file string, messages ...string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repoDir, file), []byte(file+"\n"), 0o644); err != nil {
+		t.Fatalf("write %s: %v", file, err)
+	}
+	runGit(t, repoDir, "add", file)
+	args := []string{"commit"}
+	for _, message := range messages {
+		args = append(args, "-m", message)
+	}
+	runGit(t, repoDir, args...)
+	return strings.TrimSpace(runGit(t, repoDir, "rev-parse", "HEAD"))
+}
+
+func TestGetHistoryFiltersByPaths(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+	if err := os.MkdirAll(filepath.Join(repoDir, "zeebe", "broker"), 0o755); err != nil {
+		t.Fatalf("mkdir zeebe: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, "operate"), 0o755); err != nil {
+		t.Fatalf("mkdir operate: %v", err)
+	}
+	writeAndCommit(t, repoDir, "zeebe/broker/Broker.java", "feat: broker (#1)")
+	writeAndCommit(t, repoDir, "operate/App.java", "feat: operate (#2)")
+	writeAndCommit(t, repoDir, "zeebe/README.md", "docs: zeebe readme (#3)")
+
+	tests := map[string]struct {
+		include  []string
+		exclude  []string
+		subjects []string
+	}{
+		"No paths":          {subjects: []string{"docs: zeebe readme (#3)", "feat: operate (#2)", "feat: broker (#1)", "branch-b"}},
+		"Include component": {include: []string{"zeebe/**"}, subjects: []string{"docs: zeebe readme (#3)", "feat: broker (#1)"}},
+		"Include and exclude": {
+			include:  []string{"zeebe/**"},
+			exclude:  []string{"**/*.md"},
+			subjects: []string{"feat: broker (#1)"},
+		},
+		"Exclude only":     {exclude: []string{"zeebe/**", "*.txt"}, subjects: []string{"feat: operate (#2)"}},
+		"Multiple include": {include: []string{"operate/**", "b.txt"}, subjects: []string{"feat: operate (#2)", "branch-b"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			commits := getHistory(t, repoDir, base, "HEAD", HistoryOptions{Mode: AllMode, IncludePaths: tc.include, ExcludePaths: tc.exclude})
+
+			var subjects []string
+			for _, commit := range commits {
+				subjects = append(subjects, commit.Subject)
+			}
+			assert.Equal(t, tc.subjects, subjects)
+		})
+	}
+}
+
+func TestGetHistoryFiltersMergesByMergedChanges(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+	runGit(t, repoDir, "merge", "--no-ff", "branch-a", "-m", "Merge pull request #7 from camunda/branch-a")
+
+	assert.Len(t, getHistory(t, repoDir, base, "HEAD", HistoryOptions{Mode: MergesMode, IncludePaths: []string{"a.txt"}}), 1)
+	assert.Empty(t, getHistory(t, repoDir, base, "HEAD", HistoryOptions{Mode: MergesMode, IncludePaths: []string{"b.txt"}}))
+}
+
+func TestGetHistoryInvalidRange(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+
+	_, err := GetHistory(repoDir, base, "unknown-revision", HistoryOptions{Mode: MergesMode})
+
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestResolveCommit(t *testing.T) {
+	repoDir, base, branchA, _ := prepareDivergedRepo(t)
+	runGit(t, repoDir, "tag", "-a", "1.0.0", "-m", "release", base)
+
+	commit, err := ResolveCommit(repoDir, "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, base, commit)
+
+	commit, err = ResolveCommit(repoDir, "branch-a")
+	assert.NoError(t, err)
+	assert.Equal(t, branchA, commit)
+
+	_, err = ResolveCommit(repoDir, "unknown-revision")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func getHistory(t *testing.T, path, start, end string, options HistoryOptions) []Commit {
+	t.Helper()
+	commits, err := GetHistory(path, start, end, options)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	return commits
 }
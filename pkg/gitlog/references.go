@@ -0,0 +1,415 @@
+package gitlog
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	idGroup    = "id"
+	ownerGroup = "owner"
+	repoGroup  = "repo"
+)
+
+// DefaultKeywords contains the GitHub closing keywords as well as the keywords used by
+// the Camunda backport and merge tooling.
+var DefaultKeywords = []string{
+	"close", "closes", "closed",
+	"fix", "fixes", "fixed",
+	"resolve", "resolves", "resolved",
+	"related", "relates",
+	"merge", "merges",
+	"backport", "backports", "back port", "back ports",
+}
+
+// ReferenceRules configure which lines of a commit message are scanned for issue
+// references and which references are recognized on these lines.
+type ReferenceRules struct {
+	// Keywords a line has to start with to be scanned for references.
+	Keywords []string `yaml:"keywords"`
+	// Repositories in owner/repo notation whose short and URL references are recognized.
+	Repositories []string `yaml:"repositories"`
+	// Patterns are additional regular expressions with a named group "id" and the
+	// optional named groups "owner" and "repo".
+	Patterns []string `yaml:"patterns"`
+	// Aliases map previous names of renamed repositories to their current name, both in
+	// owner/repo notation. References to a previous name belong to the current repository.
+	Aliases map[string]string `yaml:"aliases"`
+}
+
+type ReferenceSource string
+
+const (
+	// CommitSource references are found in the commit message.
+	CommitSource ReferenceSource = "commit"
+	// ClosingIssueSource references are linked to the pull request of the commit on GitHub.
+	ClosingIssueSource ReferenceSource = "closing-issue"
+	// PullRequestSource references are found in the title or body of the pull request of the commit.
+	PullRequestSource ReferenceSource = "pull-request"
+)
+
+// IssueReference is an issue or pull request referenced by a commit.
+type IssueReference struct {
+	Owner  string
+	Repo   string
+	ID     int
+	Commit *Commit
+	Source ReferenceSource
+}
+
+// Repository returns the repository of the referenced issue in owner/repo notation.
+func (r IssueReference) Repository() string {
+	return r.Owner + "/" + r.Repo
+}
+
+func (r IssueReference) URL() string {
+	return fmt.Sprintf("https://github.com/%s/%s/issues/%d", r.Owner, r.Repo, r.ID)
+}
+
+func (r IssueReference) String() string {
+	return fmt.Sprintf("%s#%d", r.Repository(), r.ID)
+}
+
+// Key identifies the referenced issue in maps, repositories are compared case insensitive
+// like on GitHub.
+func (r IssueReference) Key() string {
+	return strings.ToLower(r.String())
+}
+
+// SameIssue reports whether the references point to the same issue, repositories are
+// compared case insensitive like on GitHub.
+func (r IssueReference) SameIssue(other IssueReference) bool {
+	return r.Key() == other.Key()
+}
+
+// AppendUnique appends the additional references which are not yet contained in
+// references, keeping the first reference for every issue.
+func AppendUnique(references []IssueReference, additional ...IssueReference) []IssueReference {
+	seen := map[string]bool{}
+	for _, reference := range references {
+		seen[reference.Key()] = true
+	}
+
+	for _, reference := range additional {
+		if !seen[reference.Key()] {
+			seen[reference.Key()] = true
+			references = append(references, reference)
+		}
+	}
+
+	return references
+}
+
+// ParseRepository splits a repository in owner/repo notation.
+func ParseRepository(repository string) (string, string, error) {
+	owner, repo, found := strings.Cut(repository, "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/repo", repository)
+	}
+	return owner, repo, nil
+}
+
+// ParseIssueReference parses an issue given as number, e.g. 123 or #123, which belongs to
+// the default repository, or in owner/repo#123 notation.
+func ParseIssueReference(value, defaultOwner, defaultRepo string) (IssueReference, error) {
+	repository, number, found := strings.Cut(value, "#")
+	if !found {
+		number = repository
+		repository = ""
+	}
+
+	owner, repo := defaultOwner, defaultRepo
+	if repository != "" {
+		var err error
+		owner, repo, err = ParseRepository(repository)
+		if err != nil {
+			return IssueReference{}, fmt.Errorf("invalid issue %q: %w", value, err)
+		}
+	}
+
+	id, err := strconv.Atoi(number)
+	if err != nil || id <= 0 {
+		return IssueReference{}, fmt.Errorf("invalid issue %q, expected a number or owner/repo#number", value)
+	}
+
+	return IssueReference{Owner: owner, Repo: repo, ID: id}, nil
+}
+
+// LoadReferenceRules reads reference rules from a YAML file.
+func LoadReferenceRules(path string) (ReferenceRules, error) {
+	var rules ReferenceRules
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("unable to read reference rules: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		return rules, fmt.Errorf("unable to parse reference rules %s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+const (
+	// referencePrefix anchors references at the start of a word, so that e.g. the
+	// repository in xcamunda/camunda#1 is not mistaken for camunda/camunda.
+	referencePrefix = `(?:^|[^\w./-])`
+	urlPrefix       = `(?:https?://(?:www\.)?github\.com/)?`
+)
+
+// repositoryReferenceRegex matches references to issues and pull requests of any
+// repository in owner/repo#123 notation or as GitHub URL.
+var repositoryReferenceRegex = regexp.MustCompile(referencePrefix + urlPrefix +
+	`(?P<owner>[A-Za-z0-9][A-Za-z0-9-]*)/(?P<repo>[\w.-]+?)(?:#|/issues/|/pull/)(?P<id>\d+)`)
+
+type referencePattern struct {
+	regex      *regexp.Regexp
+	group      int
+	ownerGroup int
+	repoGroup  int
+	owner      string
+	repo       string
+}
+
+type currentRepository struct {
+	owner string
+	repo  string
+}
+
+type Extractor struct {
+	owner     string
+	repo      string
+	lineRegex *regexp.Regexp
+	patterns  []referencePattern
+	aliases   map[string]currentRepository
+}
+
+// NewExtractor compiles the given rules. Keywords default to DefaultKeywords, plain
+// "#123" references are always recognized and resolved to the given default repository.
+// References in owner/repo#123 notation and GitHub URLs are recognized for every
+// repository, the configured repositories are recognized in owner/repo/123 notation too.
+// References to an alias are resolved to its current repository, aliases are recognized
+// in owner/repo/123 notation like the configured repositories.
+func NewExtractor(owner, repo string, rules ReferenceRules) (*Extractor, error) {
+	keywords := rules.Keywords
+	if len(keywords) == 0 {
+		keywords = DefaultKeywords
+	}
+
+	var alternatives []string
+	for _, keyword := range keywords {
+		words := strings.Fields(keyword)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		alternatives = append(alternatives, strings.Join(words, `\s?`))
+	}
+	if len(alternatives) == 0 {
+		return nil, fmt.Errorf("at least one reference keyword is required")
+	}
+
+	extractor := &Extractor{
+		owner:     owner,
+		repo:      repo,
+		lineRegex: regexp.MustCompile(`(?im)^\s*([*-]\s*)?(` + strings.Join(alternatives, "|") + `)\s+.*$`),
+		patterns: []referencePattern{{
+			regex:      regexp.MustCompile(`\s#(\d+)`),
+			group:      1,
+			ownerGroup: -1,
+			repoGroup:  -1,
+			owner:      owner,
+			repo:       repo,
+		}},
+		aliases: map[string]currentRepository{},
+	}
+
+	repositories := slices.Clone(rules.Repositories)
+	for alias, name := range rules.Aliases {
+		if _, _, err := ParseRepository(alias); err != nil {
+			return nil, fmt.Errorf("invalid alias: %w", err)
+		}
+		currentOwner, currentRepo, err := ParseRepository(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %s: %w", alias, err)
+		}
+		extractor.aliases[strings.ToLower(alias)] = currentRepository{owner: currentOwner, repo: currentRepo}
+
+		if !slices.ContainsFunc(repositories, func(configured string) bool {
+			return strings.EqualFold(configured, alias)
+		}) {
+			repositories = append(repositories, alias)
+		}
+	}
+	// aliases are iterated in random order
+	sort.Strings(repositories[len(rules.Repositories):])
+
+	for _, repository := range repositories {
+		referencedOwner, referencedRepo, err := ParseRepository(repository)
+		if err != nil {
+			return nil, err
+		}
+
+		regex := regexp.MustCompile(referencePrefix + urlPrefix + `(?i:` + regexp.QuoteMeta(referencedOwner) + `/` + regexp.QuoteMeta(referencedRepo) + `)(?:/issues|/pull)?(?:/|#)(\d+)`)
+		extractor.patterns = append(extractor.patterns, referencePattern{
+			regex:      regex,
+			group:      1,
+			ownerGroup: -1,
+			repoGroup:  -1,
+			owner:      referencedOwner,
+			repo:       referencedRepo,
+		})
+	}
+
+	for _, pattern := range rules.Patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference pattern %q: %w", pattern, err)
+		}
+
+		group := regex.SubexpIndex(idGroup)
+		if group < 0 {
+			return nil, fmt.Errorf("reference pattern %q has no named group %q", pattern, idGroup)
+		}
+		extractor.patterns = append(extractor.patterns, referencePattern{
+			regex:      regex,
+			group:      group,
+			ownerGroup: regex.SubexpIndex(ownerGroup),
+			repoGroup:  regex.SubexpIndex(repoGroup),
+			owner:      owner,
+			repo:       repo,
+		})
+	}
+
+	extractor.patterns = append(extractor.patterns, referencePattern{
+		regex:      repositoryReferenceRegex,
+		group:      repositoryReferenceRegex.SubexpIndex(idGroup),
+		ownerGroup: repositoryReferenceRegex.SubexpIndex(ownerGroup),
+		repoGroup:  repositoryReferenceRegex.SubexpIndex(repoGroup),
+		owner:      owner,
+		repo:       repo,
+	})
+
+	return extractor, nil
+}
+
+// ExtractIssueIds returns the issues referenced by the given commits. Every issue is
+// only returned once, together with the first commit which referenced it.
+//
+// Besides the keyword lines of the message, the pull request number GitHub appends
+// to the subject of squash merged commits is returned for single parent commits.
+func (e *Extractor) ExtractIssueIds(commits []Commit) []IssueReference {
+	seen := map[string]bool{}
+	var references []IssueReference
+
+	for i := range commits {
+		commit := &commits[i]
+		for _, reference := range e.commitReferences(commit) {
+			if !seen[reference.Key()] {
+				seen[reference.Key()] = true
+				reference.Commit = commit
+				reference.Source = CommitSource
+				references = append(references, reference)
+			}
+		}
+	}
+
+	return references
+}
+
+// ExtractFromText returns the issues referenced by the keyword lines of an arbitrary
+// text, e.g. the body of a pull request. Every issue is only returned once.
+func (e *Extractor) ExtractFromText(text string) []IssueReference {
+	var references []IssueReference
+	for _, line := range e.lineRegex.FindAllString(text, -1) {
+		references = AppendUnique(references, e.referencesInLine(line)...)
+	}
+	return references
+}
+
+func (e *Extractor) commitReferences(commit *Commit) []IssueReference {
+	var references []IssueReference
+
+	if !commit.IsMerge() {
+		if pullRequest, ok := commit.PullRequest(); ok {
+			references = append(references, IssueReference{Owner: e.owner, Repo: e.repo, ID: pullRequest})
+		}
+	}
+
+	for _, line := range e.lineRegex.FindAllString(commit.Message(), -1) {
+		references = append(references, e.referencesInLine(line)...)
+	}
+
+	return references
+}
+
+// referencesInLine returns the references matched by all patterns in the order of
+// their appearance in the line. If the matches of several patterns overlap, only the
+// match of the first pattern is kept.
+func (e *Extractor) referencesInLine(line string) []IssueReference {
+	type match struct {
+		position  int
+		end       int
+		reference IssueReference
+	}
+	var matches []match
+
+	for _, pattern := range e.patterns {
+		for _, indices := range pattern.regex.FindAllStringSubmatchIndex(line, -1) {
+			id := submatch(line, indices, pattern.group)
+			if id == "" {
+				continue
+			}
+
+			issueId, err := strconv.Atoi(id)
+			if err != nil {
+				// numbers which overflow an int cannot be issue ids
+				continue
+			}
+
+			reference := IssueReference{Owner: pattern.owner, Repo: pattern.repo, ID: issueId}
+			if owner := submatch(line, indices, pattern.ownerGroup); owner != "" {
+				reference.Owner = owner
+			}
+			if repo := submatch(line, indices, pattern.repoGroup); repo != "" {
+				reference.Repo = repo
+			}
+			if current, ok := e.aliases[strings.ToLower(reference.Repository())]; ok {
+				reference.Owner, reference.Repo = current.owner, current.repo
+			}
+			matches = append(matches, match{position: indices[0], end: indices[1], reference: reference})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].position < matches[j].position
+	})
+
+	references := make([]IssueReference, 0, len(matches))
+	end := 0
+	for _, m := range matches {
+		if m.position < end {
+			continue
+		}
+		end = m.end
+		references = append(references, m.reference)
+	}
+	return references
+}
+
+func submatch(line string, indices []int, group int) string {
+	if group < 0 || indices[2*group] < 0 {
+		return ""
+	}
+	return line[indices[2*group]:indices[2*group+1]]
+}
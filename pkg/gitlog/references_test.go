@@ -0,0 +1,197 @@
+package gitlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractorRules(t *testing.T) {
+	tests := map[string]struct {
+		rules    ReferenceRules
+		message  string
+		issueIds []int
+	}{
+		"Any repository": {
+			rules:    ReferenceRules{Repositories: []string{"camunda/connectors"}},
+			message:  "closes camunda/connectors#412, camunda/camunda#1 and #2",
+			issueIds: []int{412, 1, 2},
+		},
+		"Configured repository in path notation": {
+			rules:    ReferenceRules{Repositories: []string{"camunda/connectors"}},
+			message:  "closes camunda/connectors/412 and camunda/operate/3",
+			issueIds: []int{412},
+		},
+		"Multiple repositories": {
+			rules:    ReferenceRules{Repositories: []string{"camunda/operate", "camunda/identity"}},
+			message:  "closes https://github.com/camunda/operate/issues/3 and camunda/identity#4",
+			issueIds: []int{3, 4},
+		},
+		"Custom keywords": {
+			rules:    ReferenceRules{Keywords: []string{"implements"}},
+			message:  "closes #1\nimplements #2",
+			issueIds: []int{2},
+		},
+		"Multi word keyword": {
+			rules:    ReferenceRules{Keywords: []string{"part of"}},
+			message:  "part of #5\npartof #6",
+			issueIds: []int{5, 6},
+		},
+		"Custom pattern": {
+			rules:    ReferenceRules{Patterns: []string{`ISSUE-(?P<id>\d+)`}},
+			message:  "closes ISSUE-7 and #8",
+			issueIds: []int{7, 8},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			extractor, err := NewExtractor("camunda", "camunda", tc.rules)
+			assert.NoError(t, err)
+
+			issueIds := referenceIds(extractor.ExtractIssueIds([]Commit{{Subject: tc.message}}))
+			assert.Equal(t, tc.issueIds, issueIds)
+		})
+	}
+}
+
+func TestExtractorRepositories(t *testing.T) {
+	extractor, err := NewExtractor("camunda", "camunda", ReferenceRules{
+		Repositories: []string{"camunda/camunda", "camunda/connectors"},
+		Patterns:     []string{`(?P<owner>[\w-]+)/(?P<repo>[\w-]+)!(?P<id>\d+)`, `GH-(?P<id>\d+)`},
+	})
+	assert.NoError(t, err)
+
+	references := extractor.ExtractIssueIds([]Commit{
+		{SHA: "a", Subject: "closes #1, camunda/connectors#1 and https://github.com/camunda/connectors/issues/412"},
+		{SHA: "b", Subject: "closes camunda/camunda#1, camunda/operate!5 and GH-6"},
+	})
+
+	var keys []string
+	for _, reference := range references {
+		keys = append(keys, reference.String())
+	}
+	assert.Equal(t, []string{"camunda/camunda#1", "camunda/connectors#1", "camunda/connectors#412", "camunda/operate#5", "camunda/camunda#6"}, keys)
+	assert.Equal(t, "a", references[1].Commit.SHA)
+	assert.Equal(t, "b", references[3].Commit.SHA)
+	assert.Equal(t, "https://github.com/camunda/connectors/issues/412", references[2].URL())
+	assert.Equal(t, "camunda/operate", references[3].Repository())
+}
+
+func TestExtractorAliases(t *testing.T) {
+	extractor, err := NewExtractor("camunda", "camunda", ReferenceRules{
+		Repositories: []string{"camunda/camunda"},
+		Aliases:      map[string]string{"camunda/zeebe": "camunda/camunda"},
+	})
+	assert.NoError(t, err)
+
+	references := extractor.ExtractIssueIds([]Commit{
+		{SHA: "a", Subject: "closes camunda/zeebe#5678 and https://github.com/Camunda/Zeebe/5555"},
+		{SHA: "b", Subject: "closes https://github.com/camunda/zeebe/pull/1 and camunda/camunda#5678"},
+	})
+
+	var keys []string
+	for _, reference := range references {
+		keys = append(keys, reference.String())
+	}
+	assert.Equal(t, []string{"camunda/camunda#5678", "camunda/camunda#5555", "camunda/camunda#1"}, keys)
+}
+
+func TestNewExtractorRejectsInvalidRules(t *testing.T) {
+	tests := map[string]ReferenceRules{
+		"Repository without owner": {Repositories: []string{"camunda"}},
+		"Repository with path":     {Repositories: []string{"camunda/camunda/issues"}},
+		"Invalid pattern":          {Patterns: []string{`(?P<id>\d+`}},
+		"Pattern without id group": {Patterns: []string{`ISSUE-(\d+)`}},
+		"Only whitespace keywords": {Keywords: []string{" "}},
+		"Alias without owner":      {Aliases: map[string]string{"zeebe": "camunda/camunda"}},
+		"Alias of invalid name":    {Aliases: map[string]string{"camunda/zeebe": "camunda"}},
+	}
+	for name, rules := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewExtractor("camunda", "camunda", rules)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadReferenceRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "references.yaml")
+	content := "keywords: [closes, fixes]\nrepositories:\n  - camunda/camunda\n  - camunda/connectors\npatterns:\n  - 'ISSUE-(?P<id>\\d+)'\naliases:\n  camunda/zeebe: camunda/camunda\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+
+	rules, err := LoadReferenceRules(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ReferenceRules{
+		Keywords:     []string{"closes", "fixes"},
+		Repositories: []string{"camunda/camunda", "camunda/connectors"},
+		Patterns:     []string{`ISSUE-(?P<id>\d+)`},
+		Aliases:      map[string]string{"camunda/zeebe": "camunda/camunda"},
+	}, rules)
+}
+
+func TestLoadReferenceRulesMissingFile(t *testing.T) {
+	_, err := LoadReferenceRules(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.Error(t, err)
+}
+
+func TestAppendUnique(t *testing.T) {
+	references := []IssueReference{{Owner: "camunda", Repo: "camunda", ID: 1}}
+
+	references = AppendUnique(references,
+		IssueReference{Owner: "Camunda", Repo: "Camunda", ID: 1},
+		IssueReference{Owner: "camunda", Repo: "connectors", ID: 1},
+		IssueReference{Owner: "camunda", Repo: "camunda", ID: 2},
+		IssueReference{Owner: "camunda", Repo: "camunda", ID: 2},
+	)
+
+	assert.Equal(t, []IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 1},
+		{Owner: "camunda", Repo: "connectors", ID: 1},
+		{Owner: "camunda", Repo: "camunda", ID: 2},
+	}, references)
+}
+
+func TestExtractFromText(t *testing.T) {
+	extractor, err := NewExtractor("camunda", "camunda", ReferenceRules{Repositories: []string{"camunda/connectors"}})
+	assert.NoError(t, err)
+
+	references := extractor.ExtractFromText("## Description\r\n\r\nSome text #3\r\n\r\ncloses #1, camunda/connectors#2\r\nrelated to #1\r\n")
+
+	assert.Equal(t, []IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 1},
+		{Owner: "camunda", Repo: "connectors", ID: 2},
+	}, references)
+}
+
+func TestParseIssueReference(t *testing.T) {
+	tests := map[string]struct {
+		value     string
+		reference IssueReference
+		err       string
+	}{
+		"Number":          {value: "123", reference: IssueReference{Owner: "camunda", Repo: "camunda", ID: 123}},
+		"Hash number":     {value: "#123", reference: IssueReference{Owner: "camunda", Repo: "camunda", ID: 123}},
+		"Repository":      {value: "camunda/connectors#412", reference: IssueReference{Owner: "camunda", Repo: "connectors", ID: 412}},
+		"Invalid number":  {value: "camunda/connectors#abc", err: `invalid issue "camunda/connectors#abc"`},
+		"Negative number": {value: "-1", err: `invalid issue "-1"`},
+		"Invalid repo":    {value: "connectors#412", err: "expected owner/repo"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			reference, err := ParseIssueReference(tc.value, "camunda", "camunda")
+
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.reference, reference)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package gitlog
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	versionRegex    = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?$`)
+	preReleaseRegex = regexp.MustCompile(`^([A-Za-z]*)[.-]?(\d*)$`)
+)
+
+// Version is a semantic version parsed from a release tag, e.g. 8.5.4 or 8.6.0-alpha1.
+type Version struct {
+	Tag        string
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease string
+}
+
+func ParseVersion(tag string) (Version, bool) {
+	match := versionRegex.FindStringSubmatch(tag)
+	if match == nil {
+		return Version{}, false
+	}
+
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	patch, _ := strconv.Atoi(match[3])
+
+	return Version{Tag: tag, Major: major, Minor: minor, Patch: patch, PreRelease: match[4]}, true
+}
+
+func (v Version) IsPreRelease() bool {
+	return v.PreRelease != ""
+}
+
+func (v Version) String() string {
+	return v.Tag
+}
+
+// Compare orders versions by major, minor and patch version, where a pre-release
+// precedes its release. Pre-releases are compared by their name and trailing number,
+// so that alpha2 precedes alpha10.
+func (v Version) Compare(other Version) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	}
+
+	match := preReleaseRegex.FindStringSubmatch(v.PreRelease)
+	otherMatch := preReleaseRegex.FindStringSubmatch(other.PreRelease)
+	if match == nil || otherMatch == nil || match[1] != otherMatch[1] {
+		return strings.Compare(v.PreRelease, other.PreRelease)
+	}
+
+	number, _ := strconv.Atoi(match[2])
+	otherNumber, _ := strconv.Atoi(otherMatch[2])
+	return cmp.Compare(number, otherNumber)
+}
+
+// PreviousRelease selects the predecessor of the target version from the given versions:
+//   - the previous pre-release of the same version for a pre-release, e.g. 8.6.0-alpha1 for 8.6.0-alpha2
+//   - the previous patch release of the same minor version for a patch release, e.g. 8.5.3 for 8.5.4
+//   - the .0 release of the previous minor version for a new minor version, e.g. 8.5.0 for 8.6.0 or 8.6.0-alpha1
+//   - the latest minor .0 release of the previous major version for a new major version
+func PreviousRelease(target Version, versions []Version) (Version, bool) {
+	var candidate Version
+	found := false
+
+	consider := func(version Version) {
+		if !found || version.Compare(candidate) > 0 {
+			candidate = version
+			found = true
+		}
+	}
+
+	if target.IsPreRelease() {
+		for _, version := range versions {
+			if version.IsPreRelease() && version.Major == target.Major && version.Minor == target.Minor &&
+				version.Patch == target.Patch && version.Compare(target) < 0 {
+				consider(version)
+			}
+		}
+		if found {
+			return candidate, true
+		}
+	}
+
+	for _, version := range versions {
+		if version.IsPreRelease() {
+			continue
+		}
+
+		switch {
+		case target.Patch > 0:
+			if version.Major == target.Major && version.Minor == target.Minor && version.Patch < target.Patch {
+				consider(version)
+			}
+		case target.Minor > 0:
+			if version.Major == target.Major && version.Minor == target.Minor-1 && version.Patch == 0 {
+				consider(version)
+			}
+		default:
+			if version.Major == target.Major-1 && version.Patch == 0 {
+				consider(version)
+			}
+		}
+	}
+
+	return candidate, found
+}
+
+// PreviousReleaseTag detects the tag of the release preceding the target revision, based
+// on the semantic version tags reachable from the target. The target has to be a version
+// tag or a revision which is tagged with a version.
+func PreviousReleaseTag(path, target string) (string, error) {
+	targetVersion, err := targetVersion(path, target)
+	if err != nil {
+		return "", err
+	}
+
+	command := exec.Command("git", "-C", path, "tag", "--merged", target)
+	log.Println(command)
+	out, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to list tags reachable from %s: %s (%w)", ErrInvalidRange, target, commandOutput(err), err)
+	}
+
+	var versions []Version
+	for _, tag := range strings.Fields(string(out)) {
+		if version, ok := ParseVersion(tag); ok && tag != targetVersion.Tag {
+			versions = append(versions, version)
+		}
+	}
+
+	previous, found := PreviousRelease(targetVersion, versions)
+	if !found {
+		return "", fmt.Errorf("%w: unable to detect the release preceding %s from the tags reachable from %s", ErrInvalidRange, targetVersion, target)
+	}
+
+	ancestor, err := isAncestor(path, previous.Tag, target)
+	if err != nil {
+		return "", err
+	}
+	if !ancestor {
+		return "", fmt.Errorf("%w: detected previous release %s is not an ancestor of %s", ErrInvalidRange, previous, target)
+	}
+
+	return previous.Tag, nil
+}
+
+func targetVersion(path, target string) (Version, error) {
+	if version, ok := ParseVersion(target); ok {
+		return version, nil
+	}
+
+	command := exec.Command("git", "-C", path, "tag", "--points-at", target)
+	log.Println(command)
+	out, err := command.Output()
+	if err != nil {
+		return Version{}, fmt.Errorf("%w: unable to list tags of %s: %s (%w)", ErrInvalidRange, target, commandOutput(err), err)
+	}
+
+	for _, tag := range strings.Fields(string(out)) {
+		if version, ok := ParseVersion(tag); ok {
+			return version, nil
+		}
+	}
+
+	return Version{}, fmt.Errorf("%w: unable to detect the version of %s, expected a version tag like 8.5.4", ErrInvalidRange, target)
+}
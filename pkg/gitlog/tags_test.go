@@ -0,0 +1,125 @@
+package gitlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]struct {
+		tag     string
+		version Version
+		valid   bool
+	}{
+		"Release":            {tag: "8.5.4", version: Version{Tag: "8.5.4", Major: 8, Minor: 5, Patch: 4}, valid: true},
+		"Prefixed release":   {tag: "v8.5.4", version: Version{Tag: "v8.5.4", Major: 8, Minor: 5, Patch: 4}, valid: true},
+		"Pre-release":        {tag: "8.6.0-alpha1", version: Version{Tag: "8.6.0-alpha1", Major: 8, Minor: 6, PreRelease: "alpha1"}, valid: true},
+		"Dotted pre-release": {tag: "8.6.0-rc.2", version: Version{Tag: "8.6.0-rc.2", Major: 8, Minor: 6, PreRelease: "rc.2"}, valid: true},
+		"Branch":             {tag: "stable/8.5", valid: false},
+		"Incomplete":         {tag: "8.5", valid: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, valid := ParseVersion(tc.tag)
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.version, version)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"Equal":                      {a: "8.5.4", b: "8.5.4", expected: 0},
+		"Patch":                      {a: "8.5.3", b: "8.5.4", expected: -1},
+		"Minor":                      {a: "8.6.0", b: "8.5.10", expected: 1},
+		"Major":                      {a: "7.99.0", b: "8.0.0", expected: -1},
+		"Pre-release before release": {a: "8.6.0-alpha1", b: "8.6.0", expected: -1},
+		"Pre-release number":         {a: "8.6.0-alpha2", b: "8.6.0-alpha10", expected: -1},
+		"Pre-release name":           {a: "8.6.0-rc1", b: "8.6.0-alpha3", expected: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, _ := ParseVersion(tc.a)
+			b, _ := ParseVersion(tc.b)
+			assert.Equal(t, tc.expected, a.Compare(b))
+			assert.Equal(t, -tc.expected, b.Compare(a))
+		})
+	}
+}
+
+func TestPreviousRelease(t *testing.T) {
+	var versions []Version
+	for _, tag := range []string{"7.9.0", "7.10.0", "7.10.3", "8.0.0", "8.4.0", "8.5.0", "8.5.1", "8.5.2", "8.5.3", "8.6.0-alpha1", "8.6.0-alpha2", "8.6.0", "8.6.1"} {
+		version, _ := ParseVersion(tag)
+		versions = append(versions, version)
+	}
+
+	tests := map[string]struct {
+		target   string
+		previous string
+		found    bool
+	}{
+		"Patch release":               {target: "8.5.4", previous: "8.5.3", found: true},
+		"First patch release":         {target: "8.6.1", previous: "8.6.0", found: true},
+		"Minor release":               {target: "8.6.0", previous: "8.5.0", found: true},
+		"First alpha of minor":        {target: "8.6.0-alpha1", previous: "8.5.0", found: true},
+		"Second alpha of minor":       {target: "8.6.0-alpha2", previous: "8.6.0-alpha1", found: true},
+		"Release candidate":           {target: "8.6.0-rc1", previous: "8.6.0-alpha2", found: true},
+		"Major release":               {target: "8.0.0", previous: "7.10.0", found: true},
+		"Missing previous minor":      {target: "8.2.0", found: false},
+		"Patch of unknown minor line": {target: "8.3.1", found: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			target, _ := ParseVersion(tc.target)
+			previous, found := PreviousRelease(target, versions)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.previous, previous.Tag)
+		})
+	}
+}
+
+func TestPreviousReleaseTag(t *testing.T) {
+	repoDir, base, _, _ := prepareDivergedRepo(t)
+	runGit(t, repoDir, "tag", "8.5.0", base)
+	runGit(t, repoDir, "tag", "8.5.3", "branch-a")
+	runGit(t, repoDir, "tag", "8.6.0", "branch-b")
+	writeAndCommit(t, repoDir, "patch.txt", "patch")
+	runGit(t, repoDir, "tag", "8.6.1")
+	runGit(t, repoDir, "checkout", "branch-a")
+	writeAndCommit(t, repoDir, "backport.txt", "backport")
+	runGit(t, repoDir, "tag", "8.5.4")
+	untagged := writeAndCommit(t, repoDir, "untagged.txt", "untagged")
+
+	tests := map[string]struct {
+		target   string
+		previous string
+	}{
+		"Patch release":       {target: "8.5.4", previous: "8.5.3"},
+		"Minor release":       {target: "8.6.0", previous: "8.5.0"},
+		"Patch on other line": {target: "8.6.1", previous: "8.6.0"},
+		"Revision with tag":   {target: "branch-b", previous: "8.6.0"},
+		"Relative revision":   {target: "HEAD~1", previous: "8.5.3"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			previous, err := PreviousReleaseTag(repoDir, tc.target)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.previous, previous)
+		})
+	}
+
+	t.Run("Untagged revision", func(t *testing.T) {
+		_, err := PreviousReleaseTag(repoDir, untagged)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+
+	t.Run("Version without predecessor", func(t *testing.T) {
+		_, err := PreviousReleaseTag(repoDir, "8.5.0")
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+}
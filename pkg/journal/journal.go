@@ -0,0 +1,145 @@
+// Package journal records the outcome of labeling single issues in a local file, so
+// that interrupted runs can be resumed without processing all issues again.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/report"
+)
+
+// Entry is a single line of the journal, written as JSON.
+type Entry struct {
+	Time         time.Time      `json:"time"`
+	Label        string         `json:"label"`
+	From         string         `json:"from"`
+	FromCommit   string         `json:"fromCommit"`
+	Target       string         `json:"target"`
+	TargetCommit string         `json:"targetCommit"`
+	Repository   string         `json:"repository"`
+	Issue        int            `json:"issue"`
+	Outcome      report.Outcome `json:"outcome"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// Run identifies the entries of a run by the label and the commits of the git range it
+// processes, so that the entries of a tag are not resumed after it was moved. From and
+// Target are the revisions as given, they are only recorded.
+type Run struct {
+	Label        string
+	From         string
+	FromCommit   string
+	Target       string
+	TargetCommit string
+}
+
+func (r Run) matches(entry Entry) bool {
+	return entry.Label == r.Label && entry.FromCommit == r.FromCommit && entry.TargetCommit == r.TargetCommit
+}
+
+// Journal appends entries of a run to the journal file. It is safe for concurrent use.
+type Journal struct {
+	run   Run
+	mutex sync.Mutex
+	file  *os.File
+	now   func() time.Time
+}
+
+// Open opens the journal file for appending entries of the given run, the file and its
+// directory are created if they do not exist.
+func Open(path string, run Run) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory of journal %s: %w", path, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open journal %s: %w", path, err)
+	}
+	return &Journal{run: run, file: file, now: time.Now}, nil
+}
+
+// Record writes the outcome of labeling an issue. Every entry is written directly to the
+// file, so that it survives a crash of the process.
+func (j *Journal) Record(result report.Result) error {
+	line, err := json.Marshal(Entry{
+		Time:         j.now().UTC(),
+		Label:        j.run.Label,
+		From:         j.run.From,
+		FromCommit:   j.run.FromCommit,
+		Target:       j.run.Target,
+		TargetCommit: j.run.TargetCommit,
+		Repository:   result.Repository,
+		Issue:        result.Issue,
+		Outcome:      result.Outcome,
+		Error:        result.Error,
+	})
+	if err != nil {
+		return err
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write journal %s: %w", j.file.Name(), err)
+	}
+	return nil
+}
+
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Completed reads the journal file and returns the issues which were processed
+// successfully in a previous attempt of the given run, i.e. labeled or skipped because
+// they cannot be labeled, by the key of their reference. A missing journal file has no
+// completed issues.
+func Completed(path string, run Run) (map[string]bool, error) {
+	completed := map[string]bool{}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return completed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open journal %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			// the last line may be truncated if the process was killed while writing
+			continue
+		}
+		if !run.matches(entry) || entry.Outcome == report.Failed {
+			continue
+		}
+		owner, repo, err := gitlog.ParseRepository(entry.Repository)
+		if err != nil {
+			// entries are only written for issues of valid repositories
+			continue
+		}
+		completed[gitlog.IssueReference{Owner: owner, Repo: repo, ID: entry.Issue}.Key()] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read journal %s: %w", path, err)
+	}
+
+	return completed, nil
+}
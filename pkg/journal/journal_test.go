@@ -0,0 +1,122 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	"github.com/camunda/zeebe-changelog/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	run := Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+
+	journal, err := Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 1, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 2, Outcome: report.Failed, Error: "502 Bad Gateway"}))
+	assert.NoError(t, journal.Record(report.Result{Repository: "Camunda/Zeebe", Issue: 3, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 5, Outcome: report.Skipped, Error: "404 Not Found"}))
+	assert.NoError(t, journal.Close())
+
+	other, err := Open(path, Run{Label: "version:8.6.1", From: "8.6.0", FromCommit: "d4e5f6", Target: "8.6.1", TargetCommit: "a7b8c9"})
+	assert.NoError(t, err)
+	assert.NoError(t, other.Record(report.Result{Repository: "camunda/camunda", Issue: 4, Outcome: report.Labeled}))
+	assert.NoError(t, other.Close())
+
+	completed, err := Completed(path, run)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"camunda/camunda#1": true, "camunda/zeebe#3": true, "camunda/camunda#5": true}, completed)
+	assert.True(t, completed[gitlog.IssueReference{Owner: "camunda", Repo: "Zeebe", ID: 3}.Key()])
+}
+
+func TestCompleted_RetriedFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	run := Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+
+	journal, err := Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 2, Outcome: report.Failed, Error: "502 Bad Gateway"}))
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 2, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Close())
+
+	completed, err := Completed(path, run)
+
+	assert.NoError(t, err)
+	assert.True(t, completed["camunda/camunda#2"])
+}
+
+func TestCompleted_MovedTag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	run := Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+
+	journal, err := Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 1, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Close())
+
+	tests := map[string]Run{
+		"Moved start tag":  {Label: run.Label, From: run.From, FromCommit: "c3b2a1", Target: run.Target, TargetCommit: run.TargetCommit},
+		"Moved target tag": {Label: run.Label, From: run.From, FromCommit: run.FromCommit, Target: run.Target, TargetCommit: "f6e5d4"},
+	}
+	for name, moved := range tests {
+		t.Run(name, func(t *testing.T) {
+			completed, err := Completed(path, moved)
+
+			assert.NoError(t, err)
+			assert.Empty(t, completed)
+		})
+	}
+}
+
+func TestCompleted_SameCommitsOfOtherRevisions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	run := Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+
+	journal, err := Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 1, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Close())
+
+	completed, err := Completed(path, Run{Label: run.Label, From: "a1b2c3", FromCommit: run.FromCommit, Target: "stable/8.6", TargetCommit: run.TargetCommit})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"camunda/camunda#1": true}, completed)
+}
+
+func TestCompleted_MissingJournal(t *testing.T) {
+	completed, err := Completed(filepath.Join(t.TempDir(), "missing.jsonl"), Run{Label: "label"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, completed)
+}
+
+func TestCompleted_TruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	content := `{"label":"label","from":"a","fromCommit":"a1","target":"b","targetCommit":"b1","repository":"camunda/camunda","issue":1,"outcome":"labeled"}
+{"label":"label","from":"a","fromCommit":"a1","target":"b","targetCommit":"b1","repository":"camu`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	completed, err := Completed(path, Run{Label: "label", From: "a", FromCommit: "a1", Target: "b", TargetCommit: "b1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"camunda/camunda#1": true}, completed)
+}
+
+func TestOpen_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zcl", "journal.jsonl")
+	run := Run{Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "d4e5f6"}
+
+	journal, err := Open(path, run)
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Record(report.Result{Repository: "camunda/camunda", Issue: 1, Outcome: report.Labeled}))
+	assert.NoError(t, journal.Close())
+
+	completed, err := Completed(path, run)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"camunda/camunda#1": true}, completed)
+}
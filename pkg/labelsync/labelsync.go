@@ -0,0 +1,39 @@
+// Package labelsync determines the issues which lose a release label when the label is
+// reconciled with the issues referenced in the git range of the release.
+package labelsync
+
+import (
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+)
+
+// Desired are the issues and pull requests which should carry the label, keyed by the
+// key of their reference, so that repositories are compared case insensitive.
+type Desired map[string]bool
+
+// NewDesired returns the referenced issues as desired issues. The references have to
+// include the issues which are skipped on resume, since they are still part of the range.
+func NewDesired(references []gitlog.IssueReference) Desired {
+	desired := make(Desired, len(references))
+	for _, reference := range references {
+		desired[reference.Key()] = true
+	}
+	return desired
+}
+
+// Contains reports whether the issue of the repository should carry the label.
+func (d Desired) Contains(owner, repo string, number int) bool {
+	return d[gitlog.IssueReference{Owner: owner, Repo: repo, ID: number}.Key()]
+}
+
+// Stale returns the issues and pull requests of the repository which carry the label,
+// as listed by GitHub, but are not desired.
+func (d Desired) Stale(owner, repo string, labeled []*github.Issue) []gitlog.IssueReference {
+	var stale []gitlog.IssueReference
+	for _, issue := range labeled {
+		if !d.Contains(owner, repo, issue.Number()) {
+			stale = append(stale, gitlog.IssueReference{Owner: owner, Repo: repo, ID: issue.Number()})
+		}
+	}
+	return stale
+}
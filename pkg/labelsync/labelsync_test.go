@@ -0,0 +1,58 @@
+package labelsync
+
+import (
+	"testing"
+
+	"github.com/camunda/zeebe-changelog/pkg/github"
+	"github.com/camunda/zeebe-changelog/pkg/gitlog"
+	gogithub "github.com/google/go-github/v83/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func labeledIssue(number int) *github.Issue {
+	return github.NewIssue(&gogithub.Issue{Number: gogithub.Ptr(number)})
+}
+
+func labeledPullRequest(number int) *github.Issue {
+	return github.NewIssue(&gogithub.Issue{Number: gogithub.Ptr(number), PullRequestLinks: &gogithub.PullRequestLinks{}})
+}
+
+func TestStale(t *testing.T) {
+	desired := NewDesired([]gitlog.IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 1},
+		{Owner: "camunda", Repo: "connectors", ID: 2},
+	})
+
+	stale := desired.Stale("camunda", "camunda", []*github.Issue{labeledIssue(1), labeledIssue(2), labeledIssue(3)})
+
+	assert.Equal(t, []gitlog.IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 2},
+		{Owner: "camunda", Repo: "camunda", ID: 3},
+	}, stale)
+}
+
+func TestStale_RepositoriesAreCaseInsensitive(t *testing.T) {
+	desired := NewDesired([]gitlog.IssueReference{{Owner: "Camunda", Repo: "Camunda", ID: 1}})
+
+	stale := desired.Stale("camunda", "CAMUNDA", []*github.Issue{labeledIssue(1), labeledIssue(2)})
+
+	assert.Equal(t, []gitlog.IssueReference{{Owner: "camunda", Repo: "CAMUNDA", ID: 2}}, stale)
+}
+
+func TestStale_PullRequests(t *testing.T) {
+	// GitHub lists labeled pull requests as issues, they share the numbers of issues
+	desired := NewDesired([]gitlog.IssueReference{{Owner: "camunda", Repo: "camunda", ID: 10, Source: gitlog.CommitSource}})
+
+	stale := desired.Stale("camunda", "camunda", []*github.Issue{labeledPullRequest(10), labeledPullRequest(11), labeledIssue(12)})
+
+	assert.Equal(t, []gitlog.IssueReference{
+		{Owner: "camunda", Repo: "camunda", ID: 11},
+		{Owner: "camunda", Repo: "camunda", ID: 12},
+	}, stale)
+}
+
+func TestStale_NothingDesired(t *testing.T) {
+	stale := NewDesired(nil).Stale("camunda", "camunda", []*github.Issue{labeledPullRequest(1)})
+
+	assert.Equal(t, []gitlog.IssueReference{{Owner: "camunda", Repo: "camunda", ID: 1}}, stale)
+}
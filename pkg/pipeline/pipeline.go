@@ -0,0 +1,187 @@
+// Package pipeline runs a sequence of steps and records their status in a state file, so
+// that a failed run can be resumed at the failed step instead of starting over.
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Status string
+
+const (
+	Completed Status = "completed"
+	Failed    Status = "failed"
+	Skipped   Status = "skipped"
+)
+
+// Step is a named step of the pipeline. Run is told whether the step failed in a previous
+// attempt of the same run, e.g. to resume its own progress.
+type Step struct {
+	Name string
+	Run  func(retry bool) error
+}
+
+// Run identifies a pipeline run, the state of another run is not resumed.
+type Run struct {
+	// Repository is the GitHub repository of the run in owner/repo notation.
+	Repository string `json:"repository"`
+	Label      string `json:"label"`
+	// From is the resolved start revision of the range, e.g. the previous release tag
+	// instead of 'auto'. FromCommit and TargetCommit are the commits the range pointed
+	// to, so that a moved tag starts a new run.
+	From         string `json:"from"`
+	FromCommit   string `json:"fromCommit"`
+	Target       string `json:"target"`
+	TargetCommit string `json:"targetCommit"`
+}
+
+// StepState is the outcome of the last attempt of a step.
+type StepState struct {
+	Status Status    `json:"status"`
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// State is the content of the state file.
+type State struct {
+	Run   Run                  `json:"run"`
+	Steps map[string]StepState `json:"steps"`
+}
+
+// Pipeline runs the steps in order and persists the state after every step.
+type Pipeline struct {
+	path  string
+	run   Run
+	steps []Step
+	now   func() time.Time
+}
+
+// New creates a pipeline of the steps, which records its state in the file at path. An
+// empty path disables the state file, e.g. for dry runs.
+func New(path string, run Run, steps ...Step) *Pipeline {
+	return &Pipeline{path: path, run: run, steps: steps, now: time.Now}
+}
+
+// Execute runs all steps which are neither skipped nor completed by a previous attempt of
+// the same run. It stops at the first failing step, rerunning the pipeline resumes at
+// this step.
+func (p *Pipeline) Execute(skip map[string]bool) error {
+	for name := range skip {
+		if !p.hasStep(name) {
+			return fmt.Errorf("unknown step %q", name)
+		}
+	}
+
+	state, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	for _, step := range p.steps {
+		previous := state.Steps[step.Name]
+
+		switch {
+		case skip[step.Name]:
+			log.Printf("Skipping step %s\n", step.Name)
+			state.Steps[step.Name] = StepState{Status: Skipped, Time: p.now().UTC()}
+			if err := p.save(state); err != nil {
+				return err
+			}
+			continue
+		case previous.Status == Completed:
+			log.Printf("Skipping step %s, it was completed at %s\n", step.Name, previous.Time.Format(time.RFC3339))
+			continue
+		}
+
+		retry := previous.Status == Failed
+		if retry {
+			log.Printf("Resuming step %s, it failed at %s: %s\n", step.Name, previous.Time.Format(time.RFC3339), previous.Error)
+		} else {
+			log.Printf("Running step %s\n", step.Name)
+		}
+
+		stepErr := step.Run(retry)
+		if stepErr != nil {
+			state.Steps[step.Name] = StepState{Status: Failed, Time: p.now().UTC(), Error: stepErr.Error()}
+		} else {
+			state.Steps[step.Name] = StepState{Status: Completed, Time: p.now().UTC()}
+		}
+
+		if err := p.save(state); err != nil {
+			return errors.Join(stepErr, err)
+		}
+		if stepErr != nil {
+			if p.path != "" {
+				return fmt.Errorf("step %s failed, rerun to resume with this step: %w", step.Name, stepErr)
+			}
+			return fmt.Errorf("step %s failed: %w", step.Name, stepErr)
+		}
+	}
+
+	return nil
+}
+
+func (p *Pipeline) hasStep(name string) bool {
+	for _, step := range p.steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// load reads the state of the run, the state of another run or a missing state file
+// starts the run from the beginning.
+func (p *Pipeline) load() (State, error) {
+	state := State{Run: p.run, Steps: map[string]StepState{}}
+	if p.path == "" {
+		return state, nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("unable to read pipeline state: %w", err)
+	}
+
+	var previous State
+	if err := json.Unmarshal(content, &previous); err != nil {
+		return state, fmt.Errorf("unable to parse pipeline state %s: %w", p.path, err)
+	}
+	if previous.Run != p.run {
+		log.Printf("Ignoring pipeline state %s of another run: repository %s, label %s, range %s (%s)..%s (%s)\n", p.path,
+			previous.Run.Repository, previous.Run.Label, previous.Run.From, previous.Run.FromCommit, previous.Run.Target, previous.Run.TargetCommit)
+		return state, nil
+	}
+	if previous.Steps != nil {
+		state.Steps = previous.Steps
+	}
+
+	return state, nil
+}
+
+func (p *Pipeline) save(state State) error {
+	if p.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory of pipeline state: %w", err)
+	}
+	if err := os.WriteFile(p.path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to write pipeline state: %w", err)
+	}
+	return nil
+}
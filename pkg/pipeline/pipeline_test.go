@@ -0,0 +1,155 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRun = Run{Repository: "camunda/camunda", Label: "version:8.6.0", From: "8.5.0", FromCommit: "a1b2c3", Target: "8.6.0", TargetCommit: "f6e5d4"}
+
+type recorder struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (r *recorder) step(name string) Step {
+	return Step{Name: name, Run: func(retry bool) error {
+		call := name
+		if retry {
+			call += " (retry)"
+		}
+		r.calls = append(r.calls, call)
+		return r.fail[name]
+	}}
+}
+
+func (r *recorder) pipeline(path string, run Run) *Pipeline {
+	return New(path, run, r.step("label"), r.step("verify"), r.step("generate"))
+}
+
+func TestPipeline_ResumesFailedStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	steps := &recorder{fail: map[string]error{"verify": errors.New("timeout")}}
+
+	err := steps.pipeline(path, testRun).Execute(nil)
+
+	assert.ErrorContains(t, err, "step verify failed, rerun to resume with this step: timeout")
+	assert.Equal(t, []string{"label", "verify"}, steps.calls)
+
+	state := readState(t, path)
+	assert.Equal(t, testRun, state.Run)
+	assert.Equal(t, Completed, state.Steps["label"].Status)
+	assert.Equal(t, StepState{Status: Failed, Time: state.Steps["verify"].Time, Error: "timeout"}, state.Steps["verify"])
+
+	steps.calls, steps.fail = nil, nil
+	err = steps.pipeline(path, testRun).Execute(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"verify (retry)", "generate"}, steps.calls)
+	state = readState(t, path)
+	for _, name := range []string{"label", "verify", "generate"} {
+		assert.Equal(t, Completed, state.Steps[name].Status, name)
+	}
+}
+
+func TestPipeline_Skip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	steps := &recorder{}
+
+	err := steps.pipeline(path, testRun).Execute(map[string]bool{"label": true, "verify": true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"generate"}, steps.calls)
+	assert.Equal(t, Skipped, readState(t, path).Steps["label"].Status)
+
+	// skipped steps run when the pipeline is rerun without skipping them
+	steps.calls = nil
+	assert.NoError(t, steps.pipeline(path, testRun).Execute(nil))
+	assert.Equal(t, []string{"label", "verify"}, steps.calls)
+}
+
+func TestPipeline_UnknownStep(t *testing.T) {
+	steps := &recorder{}
+
+	err := steps.pipeline("", testRun).Execute(map[string]bool{"publish": true})
+
+	assert.ErrorContains(t, err, `unknown step "publish"`)
+	assert.Empty(t, steps.calls)
+}
+
+func TestPipeline_IgnoresStateOfOtherRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	steps := &recorder{}
+	assert.NoError(t, steps.pipeline(path, testRun).Execute(nil))
+
+	steps.calls = nil
+	otherRun := Run{Label: "version:8.6.1", From: "8.6.0", Target: "8.6.1"}
+	assert.NoError(t, steps.pipeline(path, otherRun).Execute(nil))
+
+	assert.Equal(t, []string{"label", "verify", "generate"}, steps.calls)
+	assert.Equal(t, otherRun, readState(t, path).Run)
+}
+
+func TestPipeline_IgnoresStateOfOtherRepositoryOrMovedTag(t *testing.T) {
+	tests := map[string]Run{
+		"Other repository": {Repository: "camunda/connectors", Label: testRun.Label, From: testRun.From, FromCommit: testRun.FromCommit, Target: testRun.Target, TargetCommit: testRun.TargetCommit},
+		"Moved tag":        {Repository: testRun.Repository, Label: testRun.Label, From: testRun.From, FromCommit: "d4e5f6", Target: testRun.Target, TargetCommit: testRun.TargetCommit},
+		"Moved target tag": {Repository: testRun.Repository, Label: testRun.Label, From: testRun.From, FromCommit: testRun.FromCommit, Target: testRun.Target, TargetCommit: "c3b2a1"},
+	}
+	for name, otherRun := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			steps := &recorder{fail: map[string]error{"verify": errors.New("timeout")}}
+			assert.Error(t, steps.pipeline(path, testRun).Execute(nil))
+
+			steps = &recorder{}
+			assert.NoError(t, steps.pipeline(path, otherRun).Execute(nil))
+
+			assert.Equal(t, []string{"label", "verify", "generate"}, steps.calls)
+			assert.Equal(t, otherRun, readState(t, path).Run)
+		})
+	}
+}
+
+func TestPipeline_CreatesStateDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zcl", "state.json")
+	steps := &recorder{}
+
+	assert.NoError(t, steps.pipeline(path, testRun).Execute(nil))
+
+	assert.Equal(t, testRun, readState(t, path).Run)
+}
+
+func TestPipeline_WithoutStateFile(t *testing.T) {
+	steps := &recorder{fail: map[string]error{"label": errors.New("denied")}}
+
+	err := steps.pipeline("", testRun).Execute(nil)
+
+	assert.EqualError(t, err, "step label failed: denied")
+	assert.Equal(t, []string{"label"}, steps.calls)
+}
+
+func TestPipeline_InvalidState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+
+	err := (&recorder{}).pipeline(path, testRun).Execute(nil)
+
+	assert.ErrorContains(t, err, "unable to parse pipeline state")
+}
+
+func readState(t *testing.T, path string) State {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var state State
+	assert.NoError(t, json.Unmarshal(content, &state))
+	return state
+}
@@ -3,19 +3,29 @@ package progress
 import "github.com/gosuri/uiprogress"
 
 type Bar struct {
-	bar *uiprogress.Bar
+	progress *uiprogress.Progress
+	bar      *uiprogress.Bar
 }
 
+// NewProgressBar starts rendering a progress bar of the given size. Every bar is rendered
+// on its own, so that several bars can be started and stopped one after another.
 func NewProgressBar(size int) *Bar {
-	uiprogress.Start()
+	progress := uiprogress.New()
+	progress.Start()
 
-	bar := uiprogress.AddBar(size)
+	bar := progress.AddBar(size)
 	bar.AppendCompleted()
 	bar.PrependElapsed()
 
-	return &Bar{bar: bar}
+	return &Bar{progress: progress, bar: bar}
 }
 
 func (pb *Bar) Increase() {
 	pb.bar.Incr()
 }
+
+// Stop stops rendering the progress bar, so that following output is not interleaved
+// with the bar.
+func (pb *Bar) Stop() {
+	pb.progress.Stop()
+}
@@ -0,0 +1,227 @@
+// Package report collects the outcome of labeling the issues of a run and renders it as
+// summary table, JSON or Markdown.
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+type Outcome string
+
+const (
+	Labeled        Outcome = "labeled"
+	AlreadyLabeled Outcome = "already-labeled"
+	Removed        Outcome = "removed"
+	NotLabeled     Outcome = "not-labeled"
+	Skipped        Outcome = "skipped"
+	Failed         Outcome = "failed"
+	Resumed        Outcome = "resumed"
+)
+
+var (
+	// LabelOutcomes lists the outcomes of adding a label in the order they are summarized.
+	LabelOutcomes = []Outcome{Labeled, AlreadyLabeled, Skipped, Failed, Resumed}
+	// RemovalOutcomes lists the outcomes of removing a label in the order they are summarized.
+	RemovalOutcomes = []Outcome{Removed, NotLabeled, Skipped, Failed}
+)
+
+type Format string
+
+const (
+	JSONFormat     Format = "json"
+	MarkdownFormat Format = "markdown"
+)
+
+var Formats = []Format{JSONFormat, MarkdownFormat}
+
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == value {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown report format %q, expected one of: %s", value, strings.Join(formatNames(), ", "))
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(Formats))
+	for _, format := range Formats {
+		names = append(names, string(format))
+	}
+	return names
+}
+
+// Extension returns the file extension of report files in the format.
+func (f Format) Extension() string {
+	if f == MarkdownFormat {
+		return ".md"
+	}
+	return "." + string(f)
+}
+
+// Result is the outcome of labeling a single issue.
+type Result struct {
+	Repository string  `json:"repository"`
+	Issue      int     `json:"issue"`
+	URL        string  `json:"url"`
+	Outcome    Outcome `json:"outcome"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report collects the results of a run. It is safe for concurrent use.
+type Report struct {
+	Label  string
+	From   string
+	Target string
+
+	outcomes []Outcome
+	mutex    sync.Mutex
+	results  []Result
+}
+
+// New creates a report which summarizes the given outcomes.
+func New(label, from, target string, outcomes []Outcome) *Report {
+	return &Report{Label: label, From: from, Target: target, outcomes: outcomes}
+}
+
+func (r *Report) Add(result Result) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.results = append(r.results, result)
+}
+
+// Results returns the collected results ordered by repository and issue number.
+func (r *Report) Results() []Result {
+	r.mutex.Lock()
+	results := slices.Clone(r.results)
+	r.mutex.Unlock()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Repository != results[j].Repository {
+			return results[i].Repository < results[j].Repository
+		}
+		return results[i].Issue < results[j].Issue
+	})
+	return results
+}
+
+// Counts returns the number of issues per outcome.
+func (r *Report) Counts() map[Outcome]int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	counts := map[Outcome]int{}
+	for _, result := range r.results {
+		counts[result.Outcome]++
+	}
+	return counts
+}
+
+// Summary renders the number of issues per outcome and the failed issues as plain text
+// table for the console.
+func (r *Report) Summary() string {
+	var b bytes.Buffer
+	counts := r.Counts()
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OUTCOME\tISSUES")
+	total := 0
+	for _, outcome := range r.outcomes {
+		fmt.Fprintf(w, "%s\t%d\n", outcome, counts[outcome])
+		total += counts[outcome]
+	}
+	fmt.Fprintf(w, "total\t%d\n", total)
+	w.Flush()
+
+	for _, result := range r.Results() {
+		if result.Outcome == Failed || result.Outcome == Skipped {
+			fmt.Fprintf(&b, "  %s %s: %s\n", result.Outcome, result.URL, result.Error)
+		}
+	}
+
+	return b.String()
+}
+
+type jsonReport struct {
+	Label   string          `json:"label"`
+	From    string          `json:"from"`
+	Target  string          `json:"target"`
+	Summary map[Outcome]int `json:"summary"`
+	Results []Result        `json:"results"`
+}
+
+func (r *Report) JSON() ([]byte, error) {
+	counts := r.Counts()
+	summary := make(map[Outcome]int, len(r.outcomes))
+	for _, outcome := range r.outcomes {
+		summary[outcome] = counts[outcome]
+	}
+
+	results := r.Results()
+	if results == nil {
+		results = []Result{}
+	}
+
+	return json.MarshalIndent(jsonReport{
+		Label:   r.Label,
+		From:    r.From,
+		Target:  r.Target,
+		Summary: summary,
+		Results: results,
+	}, "", "  ")
+}
+
+func (r *Report) Markdown() string {
+	var b bytes.Buffer
+	counts := r.Counts()
+
+	fmt.Fprintf(&b, "# Labeling report for %s\n\n", r.Label)
+	fmt.Fprintf(&b, "Range: `%s..%s`\n\n", r.From, r.Target)
+
+	b.WriteString("| Outcome | Issues |\n|---------|--------|\n")
+	for _, outcome := range r.outcomes {
+		fmt.Fprintf(&b, "| %s | %d |\n", outcome, counts[outcome])
+	}
+
+	b.WriteString("\n| Issue | Outcome | Error |\n|-------|---------|-------|\n")
+	for _, result := range r.Results() {
+		fmt.Fprintf(&b, "| [%s#%d](%s) | %s | %s |\n", result.Repository, result.Issue, result.URL, result.Outcome, escapeMarkdownCell(result.Error))
+	}
+
+	return b.String()
+}
+
+func escapeMarkdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
+
+// Write renders the report in the format and writes it to the file.
+func (r *Report) Write(path string, format Format) error {
+	var content []byte
+	switch format {
+	case JSONFormat:
+		var err error
+		content, err = r.JSON()
+		if err != nil {
+			return err
+		}
+		content = append(content, '\n')
+	case MarkdownFormat:
+		content = []byte(r.Markdown())
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("unable to write report %s: %w", path, err)
+	}
+	return nil
+}
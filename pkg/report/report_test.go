@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	report := New("version:8.6.0", "8.5.0", "8.6.0", LabelOutcomes)
+	report.Add(Result{Repository: "camunda/camunda", Issue: 3, URL: "https://github.com/camunda/camunda/issues/3", Outcome: Failed, Error: "502 Bad Gateway"})
+	report.Add(Result{Repository: "camunda/camunda", Issue: 1, URL: "https://github.com/camunda/camunda/issues/1", Outcome: Labeled})
+	report.Add(Result{Repository: "camunda/camunda", Issue: 2, URL: "https://github.com/camunda/camunda/issues/2", Outcome: Skipped, Error: "404 Not Found"})
+	report.Add(Result{Repository: "camunda/camunda", Issue: 4, URL: "https://github.com/camunda/camunda/issues/4", Outcome: Labeled})
+	report.Add(Result{Repository: "camunda/camunda", Issue: 5, URL: "https://github.com/camunda/camunda/issues/5", Outcome: AlreadyLabeled})
+	return report
+}
+
+func TestSummary(t *testing.T) {
+	expected := `OUTCOME          ISSUES
+labeled          2
+already-labeled  1
+skipped          1
+failed           1
+resumed          0
+total            5
+  skipped https://github.com/camunda/camunda/issues/2: 404 Not Found
+  failed https://github.com/camunda/camunda/issues/3: 502 Bad Gateway
+`
+
+	assert.Equal(t, expected, newTestReport().Summary())
+}
+
+func TestJSON(t *testing.T) {
+	content, err := newTestReport().JSON()
+	assert.NoError(t, err)
+
+	var decoded jsonReport
+	assert.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, "version:8.6.0", decoded.Label)
+	assert.Equal(t, map[Outcome]int{Labeled: 2, AlreadyLabeled: 1, Skipped: 1, Failed: 1, Resumed: 0}, decoded.Summary)
+	assert.Len(t, decoded.Results, 5)
+	assert.Equal(t, 1, decoded.Results[0].Issue)
+	assert.Equal(t, "502 Bad Gateway", decoded.Results[2].Error)
+}
+
+func TestMarkdown(t *testing.T) {
+	report := New("version:8.6.0", "8.5.0", "8.6.0", LabelOutcomes)
+	report.Add(Result{Repository: "camunda/camunda", Issue: 1, URL: "https://github.com/camunda/camunda/issues/1", Outcome: Failed, Error: "a | b"})
+
+	expected := "# Labeling report for version:8.6.0\n\n" +
+		"Range: `8.5.0..8.6.0`\n\n" +
+		"| Outcome | Issues |\n|---------|--------|\n" +
+		"| labeled | 0 |\n| already-labeled | 0 |\n| skipped | 0 |\n| failed | 1 |\n| resumed | 0 |\n\n" +
+		"| Issue | Outcome | Error |\n|-------|---------|-------|\n" +
+		"| [camunda/camunda#1](https://github.com/camunda/camunda/issues/1) | failed | a \\| b |\n"
+
+	assert.Equal(t, expected, report.Markdown())
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+
+	assert.NoError(t, newTestReport().Write(path, MarkdownFormat))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "| failed | 1 |")
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("markdown")
+	assert.NoError(t, err)
+	assert.Equal(t, MarkdownFormat, format)
+	assert.Equal(t, ".md", format.Extension())
+
+	_, err = ParseFormat("html")
+	assert.ErrorContains(t, err, "expected one of: json, markdown")
+}